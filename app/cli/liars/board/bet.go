@@ -9,7 +9,7 @@ import (
 // addBet takes the value selected on the keyboard and adds it to the
 // bet slice and screen.
 func (b *Board) addBet(r rune) error {
-	if b.lastState.CurrentAcctID != b.accountID {
+	if b.lastState.PlayerTurn != b.accountID {
 		return errors.New("not your turn")
 	}
 
@@ -37,7 +37,7 @@ func (b *Board) addBet(r rune) error {
 
 // subBet removes a value from the bet slice and screen.
 func (b *Board) subBet() error {
-	if b.lastState.CurrentAcctID != b.accountID {
+	if b.lastState.PlayerTurn != b.accountID {
 		return errors.New("not your turn")
 	}
 
@@ -68,7 +68,7 @@ func (b *Board) subBet() error {
 
 // enterBet is called to submit a bet.
 func (b *Board) enterBet() error {
-	state, err := b.engine.QueryState(b.lastState.GameID)
+	state, err := b.engine.QueryState(b.lastState.GameID.String())
 	if err != nil {
 		return err
 	}
@@ -77,7 +77,7 @@ func (b *Board) enterBet() error {
 		return errors.New("invalid status state: " + state.Status)
 	}
 
-	if state.CurrentAcctID != b.accountID {
+	if state.PlayerTurn != b.accountID {
 		return errors.New("not your turn")
 	}
 
@@ -85,7 +85,12 @@ func (b *Board) enterBet() error {
 		return errors.New("missing bet information")
 	}
 
-	if _, err = b.engine.Bet(b.lastState.GameID, len(b.bets), b.bets[0]); err != nil {
+	suit, err := strconv.Atoi(string(b.bets[0]))
+	if err != nil {
+		return err
+	}
+
+	if _, err = b.engine.Bet(b.lastState.GameID.String(), len(b.bets), suit); err != nil {
 		return err
 	}
 
@@ -3,8 +3,10 @@ package board
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/ardanlabs/liarsdice/app/cli/liars/engine"
+	"github.com/ardanlabs/liarsdice/business/web/event"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gdamore/tcell/v2"
 )
@@ -32,6 +34,7 @@ const (
 const (
 	columnHeight = 2
 	playersX     = 3
+	ratingX      = 16
 	outX         = 22
 	betX         = 35
 	balX         = 50
@@ -51,20 +54,29 @@ var words = []string{"", "one's", "two's", "three's", "four's", "five's", "six's
 // Board represents the game board and all its state.
 type Board struct {
 	accountID common.Address
+	settings  engine.Settings
 	engine    *engine.Engine
 	config    engine.Config
 	screen    tcell.Screen
 	style     tcell.Style
 	bets      []rune
 	messages  []string
-	lastState engine.State
+	lastState event.State
 	modalUp   bool
 	modalMsg  string
 	modalFn   func(r rune)
+	watching  bool
+	replay    *replay
+	chat      []engine.ChatMessage
+	chatInput []rune
+	chatting  bool
+	ratings   map[common.Address]int
+	ratingsMu sync.Mutex
 }
 
-// New contructs a game board and renders the board.
-func New(engine *engine.Engine, accountID common.Address) (*Board, error) {
+// New contructs a game board and renders the board. New games are created
+// with the specified settings.
+func New(engine *engine.Engine, accountID common.Address, settings engine.Settings) (*Board, error) {
 	config, err := engine.Configuration()
 	if err != nil {
 		return nil, fmt.Errorf("get game configuration: %w", err)
@@ -86,11 +98,13 @@ func New(engine *engine.Engine, accountID common.Address) (*Board, error) {
 
 	board := Board{
 		accountID: accountID,
+		settings:  settings,
 		config:    config,
 		engine:    engine,
 		screen:    screen,
 		style:     style,
 		messages:  make([]string, 5),
+		ratings:   make(map[common.Address]int),
 	}
 
 	if err := board.drawInit(false); err != nil {
@@ -113,6 +127,69 @@ func (b *Board) Run() chan struct{} {
 
 // Events handles any events from the websocket. This function should be
 // registered with any code receiving the web socket events.
-func (b *Board) Events(event string, address common.Address) {
-	b.webEvents(event, address)
+func (b *Board) Events(env event.Envelope, err error) {
+	b.webEvents(env, err)
+}
+
+// rating returns the player's rating formatted for the board. The ratings
+// are cached since they only change when a game is reconciled.
+func (b *Board) rating(address common.Address) string {
+	b.ratingsMu.Lock()
+	defer b.ratingsMu.Unlock()
+
+	rating, exists := b.ratings[address]
+	if !exists {
+		r, err := b.engine.Rating(address)
+		if err != nil {
+			return "    "
+		}
+
+		rating = r.Rating
+		b.ratings[address] = rating
+	}
+
+	return fmt.Sprintf("%4d", rating)
+}
+
+// clearRatings drops the cached ratings so they are retrieved again.
+func (b *Board) clearRatings() {
+	b.ratingsMu.Lock()
+	defer b.ratingsMu.Unlock()
+
+	b.ratings = make(map[common.Address]int)
+}
+
+// JoinByCode joins the private game the invite code was created for.
+func (b *Board) JoinByCode(code string) error {
+	state, err := b.engine.JoinGameByCode(code)
+	if err != nil {
+		return err
+	}
+
+	if state, err = b.clientSeed(state.GameID.String()); err != nil {
+		return err
+	}
+
+	b.lastState = state
+
+	b.drawInit(true)
+
+	return nil
+}
+
+// Watch displays the specified game as a spectator. A spectator receives the
+// game events but can't play.
+func (b *Board) Watch(gameID string) error {
+	state, err := b.engine.Watch(gameID)
+	if err != nil {
+		return err
+	}
+
+	b.watching = true
+	b.lastState = state
+
+	b.drawInit(true)
+	b.printMessage("watching game as a spectator", false)
+
+	return nil
 }
@@ -0,0 +1,153 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/ardanlabs/liarsdice/app/cli/liars/engine"
+	"github.com/gdamore/tcell/v2"
+	"github.com/google/uuid"
+)
+
+// Game positioning values for the chat pane.
+const (
+	chatY      = boardHeight
+	chatLines  = 4
+	chatInputY = chatY + chatLines
+	chatWidth  = 110
+	chatMaxLen = 200
+)
+
+// startChat starts typing a chat message to the table.
+func (b *Board) startChat() error {
+	if b.lastState.GameID == uuid.Nil {
+		return errors.New("join a game to chat")
+	}
+
+	b.chatting = true
+	b.chatInput = []rune{}
+	b.drawChat()
+
+	return nil
+}
+
+// chatKey processes a key pressed while typing a chat message.
+func (b *Board) chatKey(ev *tcell.EventKey) error {
+	switch ev.Key() {
+	case tcell.KeyEnter:
+		return b.sendChat()
+
+	case tcell.KeyBackspace, tcell.KeyDEL, tcell.KeyDelete:
+		if len(b.chatInput) > 0 {
+			b.chatInput = b.chatInput[:len(b.chatInput)-1]
+		}
+
+	case tcell.KeyRune:
+		if len(b.chatInput) >= chatMaxLen {
+			b.screen.Beep()
+			return nil
+		}
+		b.chatInput = append(b.chatInput, ev.Rune())
+	}
+
+	b.drawChat()
+
+	return nil
+}
+
+// cancelChat stops typing the chat message without sending it.
+func (b *Board) cancelChat() {
+	b.chatting = false
+	b.chatInput = []rune{}
+	b.drawBoard(b.lastState)
+}
+
+// sendChat sends the chat message to the table. The message is shown once
+// the engine broadcasts it back.
+func (b *Board) sendChat() error {
+	message := string(b.chatInput)
+
+	b.chatting = false
+	b.chatInput = []rune{}
+	b.drawBoard(b.lastState)
+
+	if message == "" {
+		return nil
+	}
+
+	return b.engine.SendChat(b.lastState.GameID.String(), message)
+}
+
+// refreshChat retrieves the chat messages of the game and shows them.
+func (b *Board) refreshChat() {
+	if b.lastState.GameID == uuid.Nil {
+		return
+	}
+
+	msgs, err := b.engine.Chat(b.lastState.GameID.String())
+	if err != nil {
+		return
+	}
+
+	b.chat = msgs
+	b.drawChat()
+}
+
+// addChat adds a message broadcast to the table of the game and shows it.
+// Only the messages that fit in the chat pane are kept.
+func (b *Board) addChat(msg engine.ChatMessage) {
+	if msg.GameID != b.lastState.GameID.String() {
+		return
+	}
+
+	b.chat = append(b.chat, msg)
+	if len(b.chat) > chatLines {
+		b.chat = b.chat[len(b.chat)-chatLines:]
+	}
+
+	b.drawChat()
+}
+
+// drawChat draws the latest chat messages and the message being typed.
+func (b *Board) drawChat() {
+	msgs := b.chat
+	if len(msgs) > chatLines {
+		msgs = msgs[len(msgs)-chatLines:]
+	}
+
+	for i := 0; i < chatLines; i++ {
+		var line string
+		if i < len(msgs) {
+			line = fmt.Sprintf("%s: %s", b.fmtAddress(msgs[i].AccountID), msgs[i].Message)
+		}
+		b.print(1, chatY+i, fitChat(line))
+	}
+
+	input := "<c> to chat"
+	if b.chatting {
+		input = "Chat :> " + string(b.chatInput)
+	}
+	b.print(1, chatInputY, fitChat(input))
+
+	if b.chatting {
+		x := 1 + utf8.RuneCountInString(input)
+		if x > chatWidth {
+			x = chatWidth
+		}
+		b.screen.ShowCursor(x, chatInputY)
+	}
+
+	b.screen.Show()
+}
+
+// fitChat pads or cuts a line of the chat pane to the width of the pane. The
+// end of a long line is kept, so the end of the message being typed shows.
+func fitChat(line string) string {
+	runes := []rune(line)
+	if len(runes) > chatWidth {
+		runes = runes[len(runes)-chatWidth:]
+	}
+
+	return fmt.Sprintf("%-*s", chatWidth, string(runes))
+}
@@ -4,18 +4,19 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/ardanlabs/liarsdice/app/cli/liars/engine"
+	"github.com/ardanlabs/liarsdice/business/web/event"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gdamore/tcell/v2"
+	"github.com/google/uuid"
 	"github.com/mattn/go-runewidth"
 )
 
 // drawInit generates the initial game board and starts the event loop.
 func (b *Board) drawInit(active bool) error {
-	var state engine.State
-	if b.lastState.GameID != "" {
+	var state event.State
+	if b.lastState.GameID != uuid.Nil {
 		var err error
-		state, err = b.engine.QueryState(b.lastState.GameID)
+		state, err = b.engine.QueryState(b.lastState.GameID.String())
 		if err != nil {
 			return err
 		}
@@ -29,10 +30,11 @@ func (b *Board) drawInit(active bool) error {
 
 	b.print(helpX, 1, "<1-6>+   : set bet")
 	b.print(helpX, 2, "<del>    : remove bet number")
-	b.print(helpX, 3, "<l>      : call liar")
-	b.print(helpX, 4, "<n>      : new game")
-	b.print(helpX, 5, "<j>      : join game")
-	b.print(helpX, 6, "<s>      : start game")
+	b.print(helpX, 3, "<l>/<e>  : call liar/exact")
+	b.print(helpX, 4, "<o>      : odds of last bet")
+	b.print(helpX, 5, "<n>/<j>  : new/join game")
+	b.print(helpX, 6, "<s>/<c>  : start game/chat")
+	b.print(helpX, 7, "<r>/<d>  : rematch/decline")
 
 	b.print(helpX, statusY-6, "status   :")
 	b.print(helpX, statusY-5, "round    :")
@@ -52,13 +54,14 @@ func (b *Board) drawInit(active bool) error {
 	b.print(betRowX, betRowY, "                 ")
 
 	b.drawBoard(state)
+	b.refreshChat()
 
 	return nil
 }
 
 // drawBoard display the status information.
-func (b *Board) drawBoard(status engine.State) {
-	if status.GameID == "" {
+func (b *Board) drawBoard(status event.State) {
+	if status.GameID == uuid.Nil {
 		return
 	}
 
@@ -67,13 +70,17 @@ func (b *Board) drawBoard(status engine.State) {
 
 	// Print the current game status and round.
 	b.print(helpX+11, statusY-6, fmt.Sprintf("%-10s / %s", status.Status, status.GameID))
-	b.print(helpX+11, statusY-5, fmt.Sprintf("%d   ", status.Round))
+	round := fmt.Sprintf("%d          ", status.Round)
+	if status.Palifico {
+		round = fmt.Sprintf("%d palifico", status.Round)
+	}
+	b.print(helpX+11, statusY-5, round)
 
 	// Show the account who last won and lost.
 	var empty common.Address
-	if status.LastWinAcctID != empty {
-		b.print(helpX+11, statusY-3, b.fmtAddress(status.LastWinAcctID))
-		b.print(helpX+11, statusY-2, b.fmtAddress(status.LastOutAcctID))
+	if status.PlayerLastWin != empty {
+		b.print(helpX+11, statusY-3, b.fmtAddress(status.PlayerLastWin))
+		b.print(helpX+11, statusY-2, b.fmtAddress(status.PlayerLastOut))
 	}
 
 	// Show the last bet.
@@ -93,18 +100,19 @@ func (b *Board) drawBoard(status engine.State) {
 
 		// Players Column.
 		addrY := columnHeight + 2 + i
-		accountID := b.fmtAddress(cup.AccountID)
+		accountID := b.fmtAddress(cup.Player)
 		b.print(playersX+3, addrY, accountID)
+		b.print(ratingX, addrY, b.rating(cup.Player))
 
 		// Outs.
 		b.print(outX, addrY, fmt.Sprintf("%d", cup.Outs))
 
 		// Show the active player and status.
 		switch {
-		case cup.AccountID == status.CurrentAcctID:
+		case cup.Player == status.PlayerTurn:
 			b.print(playersX, addrY, "->")
 			b.print(playersX+3, addrY, accountID)
-		case cup.Outs == 3:
+		case cup.Outs == status.Rules.MaxOuts:
 			b.print(playersX, addrY, " X")
 		default:
 			b.print(playersX, addrY, "  ")
@@ -124,10 +132,13 @@ func (b *Board) drawBoard(status engine.State) {
 		b.print(boardWidth-(balWidth+2), addrY, bal)
 
 		// Show the dice for the connected account.
-		if cup.AccountID == b.accountID {
-			if cup.Dice[0] != 0 {
-				dice := fmt.Sprintf("[%d][%d][%d][%d][%d]", cup.Dice[0], cup.Dice[1], cup.Dice[2], cup.Dice[3], status.Cups[i].Dice[4])
-				b.print(myDiceX, myDiceY, dice)
+		if cup.Player == b.accountID {
+			if len(cup.Dice) > 0 && cup.Dice[0] != 0 {
+				var dice string
+				for _, d := range cup.Dice {
+					dice += fmt.Sprintf("[%d]", d)
+				}
+				b.print(myDiceX, myDiceY, fmt.Sprintf("%-15s", dice))
 			}
 		}
 	}
@@ -137,8 +148,8 @@ func (b *Board) drawBoard(status engine.State) {
 	b.print(potX, potY, fmt.Sprintf("$%.2f", pot))
 
 	// Handle active player screen changes.
-	if len(status.CupsOrder) > 0 {
-		if status.CurrentAcctID == b.accountID {
+	if len(status.ExistingPlayers) > 0 {
+		if status.PlayerTurn == b.accountID {
 			for x, r := range b.bets {
 				b.print(betRowX+x+1, betRowY, string(r))
 			}
@@ -162,6 +173,9 @@ func (b *Board) drawBoard(status engine.State) {
 		b.screen.HideCursor()
 	}
 
+	// Keep the chat pane up to date.
+	b.drawChat()
+
 	// If the model was up, show it again.
 	if b.modalUp {
 		b.showModal(b.modalMsg)
@@ -173,6 +187,7 @@ func (b *Board) drawBoard(status engine.State) {
 // drawLables places the labels on the board.
 func (b *Board) drawLables() {
 	b.print(playersX, columnHeight, "Players:")
+	b.print(ratingX, columnHeight, "Elo:")
 	b.print(outX, columnHeight, "Outs:")
 	b.print(betX, columnHeight, "Last Bet:")
 	b.print(balX, columnHeight, "  Balances:")
@@ -189,7 +204,7 @@ func (b *Board) drawSettings() {
 	b.print(helpX+11, statusY+1, b.config.Network)
 	b.print(helpX+11, statusY+2, fmt.Sprintf("%d", b.config.ChainID))
 	b.print(helpX+11, statusY+3, b.fmtAddress(b.config.ContractID))
-	b.print(helpX+11, statusY+4, b.fmtAddress(b.accountID))
+	b.print(helpX+11, statusY+4, b.fmtAddress(b.accountID)+" "+b.rating(b.accountID))
 }
 
 // PrintMessage adds a message to the message center.
@@ -5,52 +5,142 @@ import (
 	"strings"
 
 	"github.com/ardanlabs/liarsdice/app/cli/liars/engine"
-	"github.com/ethereum/go-ethereum/common"
+	"github.com/ardanlabs/liarsdice/business/web/event"
 )
 
 // webEvents handles any events from the websocket.
-func (b *Board) webEvents(event string, address common.Address) {
-	if !strings.Contains(event, "read tcp") {
-		message := fmt.Sprintf("addr: %s type: %s", b.fmtAddress(address), event)
-		b.printMessage(message, true)
+func (b *Board) webEvents(env event.Envelope, err error) {
+	// The games being played don't change a replay.
+	if b.replay != nil {
+		return
 	}
 
-	var state engine.State
-	var err error
+	if err != nil {
+		// The socket is closed when the board shuts down.
+		if !strings.Contains(err.Error(), "read tcp") {
+			b.printMessage("error: "+err.Error(), true)
+		}
+		return
+	}
+
+	// Chat messages are shown in the chat pane, not the message center.
+	if env.Type == event.TypeChat {
+		var msg engine.ChatMessage
+		if err := env.Decode(&msg); err != nil {
+			return
+		}
+		b.addChat(msg)
+		return
+	}
+
+	// Events that change the game carry its state redacted for this
+	// account, so it doesn't need to be retrieved again.
+	var payload event.StatePayload
+	if env.GameID == b.lastState.GameID {
+		if err := env.Decode(&payload); err != nil {
+			b.printMessage("error: "+err.Error(), true)
+		}
+	}
+	state := payload.State
+
+	message := fmt.Sprintf("addr: %s type: %s", b.fmtAddress(env.Actor), env.Type)
+	if payload.Action != "" {
+		message += " action: " + payload.Action
+	}
+	b.printMessage(message, true)
 
-	switch event {
-	case "start":
-		state, err = b.engine.RollDice(b.lastState.GameID)
+	switch env.Type {
+	case event.TypeStart:
+
+		// Spectators don't have dice to roll.
+		if b.watching {
+			break
+		}
+
+		state, err = b.engine.RollDice(b.lastState.GameID.String())
 		if err != nil {
 			b.printMessage("error rolling dice", true)
 		}
 
-	case "rolldice":
+	case event.TypeRollDice:
 
 		// Another player rolling the dice does not affect
 		// our display.
-		if address != b.accountID {
+		if env.Actor != b.accountID {
 			return
 		}
 
-	case "callliar":
-		state, err = b.modalWinnerLoser("*** WON ROUND ***", "*** LOST ROUND ***")
+	case event.TypeCallLiar, event.TypeCallExact:
+
+		// The last state we have is for the round that just ended. Use the
+		// published commitments to verify the dice that were rolled.
+		if err := b.engine.VerifyRound(b.lastState); err != nil {
+			b.printMessage("dice verification failed: "+err.Error(), true)
+		}
+
+		if !b.watching {
+			b.modalWinnerLoser(state, "*** WON ROUND ***", "*** LOST ROUND ***")
+		}
+
+		// Show how many dice were counted for the bet that was called.
+		if reveal := state.Reveal; reveal != nil && reveal.Round > 0 {
+			message := fmt.Sprintf("counted %d x %d's for bet %d x %d's", reveal.Total, reveal.Bet.Suit, reveal.Bet.Number, reveal.Bet.Suit)
+			b.printMessage(message, false)
+		}
+
+		state, err = b.reconcile(state)
 		if err != nil {
-			b.printMessage("winner/loser", true)
+			b.printMessage(err.Error(), true)
 		}
 
+	case event.TypeTimeout, event.TypeLeave:
+
+		// A timeout or a player leaving can end the game, so the winner
+		// needs to reconcile.
 		state, err = b.reconcile(state)
 		if err != nil {
 			b.printMessage(err.Error(), true)
 		}
 
-	case "reconcile":
-		b.modalWinnerLoser("*** WON GAME ***", "*** LOST GAME ***")
+	case event.TypeRematch:
+
+		// Once the rematch starts, the board moves to the new game.
+		rematch, err := b.engine.Rematch(b.lastState.GameID.String())
+		if err != nil {
+			b.printMessage("rematch: "+err.Error(), true)
+			break
+		}
+
+		switch {
+		case rematch.NewGameID != "":
+			state, err = b.clientSeed(rematch.NewGameID)
+			if err != nil {
+				b.printMessage("rematch: "+err.Error(), true)
+				break
+			}
+
+			b.lastState = state
+			b.printMessage("rematch started", false)
+
+		case len(rematch.Pending) == 0:
+			b.printMessage("rematch cancelled", false)
+
+		default:
+			message := fmt.Sprintf("rematch: %d accepted, press r to play again", len(rematch.Accepted))
+			b.printMessage(message, false)
+		}
+
+	case event.TypeReconcile:
+		b.clearRatings()
+
+		if !b.watching {
+			b.modalWinnerLoser(state, "*** WON GAME ***", "*** LOST GAME ***")
+		}
 	}
 
-	// If we don't have a new status, retrieve the latest.
+	// If the event didn't carry the state, retrieve the latest.
 	if state.Status == "" {
-		state, err = b.engine.QueryState(b.lastState.GameID)
+		state, err = b.engine.QueryState(b.lastState.GameID.String())
 		if err != nil {
 			return
 		}
@@ -61,18 +151,18 @@ func (b *Board) webEvents(event string, address common.Address) {
 }
 
 // reconcile the game the winner gets paid.
-func (b *Board) reconcile(status engine.State) (engine.State, error) {
+func (b *Board) reconcile(status event.State) (event.State, error) {
 	if status.Status != "gameover" {
 		return status, nil
 	}
 
-	if status.LastWinAcctID != b.accountID {
+	if status.PlayerLastWin != b.accountID {
 		return status, nil
 	}
 
-	newState, err := b.engine.Reconcile(status.GameID)
+	newState, err := b.engine.Reconcile(status.GameID.String())
 	if err != nil {
-		return engine.State{}, err
+		return event.State{}, err
 	}
 
 	return newState, nil
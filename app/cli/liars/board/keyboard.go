@@ -1,11 +1,14 @@
 package board
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strconv"
 	"unicode/utf8"
 
+	"github.com/ardanlabs/liarsdice/business/web/event"
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -30,6 +33,10 @@ func (b *Board) pollEvents() chan struct{} {
 					b.closeModal()
 					continue
 				}
+				if b.chatting {
+					b.cancelChat()
+					continue
+				}
 				close(quit)
 				return
 			}
@@ -44,6 +51,14 @@ func (b *Board) pollEvents() chan struct{} {
 				continue
 			}
 
+			// While chatting, the keys are typed into the chat message.
+			if b.chatting {
+				if err := b.chatKey(ev); err != nil {
+					b.printMessage(err.Error(), true)
+				}
+				continue
+			}
+
 			// Process the specified keys.
 			var err error
 			switch keyType {
@@ -53,6 +68,9 @@ func (b *Board) pollEvents() chan struct{} {
 			case tcell.KeyEnter:
 				err = b.enterBet()
 
+			case tcell.KeyLeft, tcell.KeyRight, tcell.KeyUp, tcell.KeyDown:
+				err = b.stepReplay(keyType)
+
 			case tcell.KeyRune:
 				err = b.processKeyEvent(ev.Rune())
 			}
@@ -70,6 +88,14 @@ func (b *Board) pollEvents() chan struct{} {
 // processKeyEvent is the first line of processing for any key that is
 // pressed during the game.
 func (b *Board) processKeyEvent(r rune) error {
+	if b.watching {
+		return errors.New("spectators can't play")
+	}
+
+	if b.replay != nil {
+		return errors.New("replays can't be played")
+	}
+
 	var err error
 
 	switch {
@@ -88,6 +114,21 @@ func (b *Board) processKeyEvent(r rune) error {
 	case r == rune('l'):
 		err = b.callLiar()
 
+	case r == rune('e'):
+		err = b.callExact()
+
+	case r == rune('o'):
+		err = b.odds()
+
+	case r == rune('r'):
+		err = b.answerRematch(true)
+
+	case r == rune('d'):
+		err = b.answerRematch(false)
+
+	case r == rune('c'):
+		err = b.startChat()
+
 	default:
 		err = errors.New("invalid selection")
 	}
@@ -106,21 +147,30 @@ func (b *Board) value(r rune) error {
 
 // newGame starts a new game.
 func (b *Board) newGame() error {
-	state, err := b.engine.NewGame()
+	state, err := b.engine.NewGame(b.settings)
 	if err != nil {
 		return err
 	}
 
+	if state, err = b.clientSeed(state.GameID.String()); err != nil {
+		return err
+	}
+
 	b.lastState = state
 
 	b.drawInit(true)
 
+	// Share the invite code so other players can join a private table.
+	if state.InviteCode != "" {
+		b.printMessage("invite "+state.InviteCode, false)
+	}
+
 	return nil
 }
 
 // joinGame adds the account to the game.
 func (b *Board) joinGame() error {
-	tables, err := b.engine.Tables(b.lastState.GameID)
+	tables, err := b.engine.Tables(b.lastState.GameID.String())
 	if err != nil {
 		return err
 	}
@@ -146,7 +196,7 @@ func (b *Board) joinGame() error {
 			return
 		}
 
-		for _, acct := range state.CupsOrder {
+		for _, acct := range state.ExistingPlayers {
 			if acct.Cmp(b.accountID) == 0 {
 				b.closeModal()
 				b.lastState = state
@@ -168,6 +218,12 @@ func (b *Board) joinGame() error {
 			return
 		}
 
+		if state, err = b.clientSeed(gameID); err != nil {
+			b.closeModal()
+			b.showModal(err.Error())
+			return
+		}
+
 		b.lastState = state
 	}
 
@@ -176,9 +232,20 @@ func (b *Board) joinGame() error {
 	return nil
 }
 
+// clientSeed generates a random seed for the player to mix into their rolls
+// so the dice don't only depend on the game engine.
+func (b *Board) clientSeed(gameID string) (event.State, error) {
+	seed := make([]byte, 16)
+	if _, err := rand.Read(seed); err != nil {
+		return event.State{}, fmt.Errorf("generate client seed: %w", err)
+	}
+
+	return b.engine.ClientSeed(gameID, hex.EncodeToString(seed))
+}
+
 // startGame start the game so it can be played.
 func (b *Board) startGame() error {
-	state, err := b.engine.QueryState(b.lastState.GameID)
+	state, err := b.engine.QueryState(b.lastState.GameID.String())
 	if err != nil {
 		return err
 	}
@@ -187,7 +254,7 @@ func (b *Board) startGame() error {
 		return errors.New("invalid status state: " + state.Status)
 	}
 
-	if _, err := b.engine.StartGame(b.lastState.GameID); err != nil {
+	if _, err := b.engine.StartGame(b.lastState.GameID.String()); err != nil {
 		return err
 	}
 
@@ -196,7 +263,7 @@ func (b *Board) startGame() error {
 
 // callLiar calls the last bet a lie.
 func (b *Board) callLiar() error {
-	state, err := b.engine.QueryState(b.lastState.GameID)
+	state, err := b.engine.QueryState(b.lastState.GameID.String())
 	if err != nil {
 		return err
 	}
@@ -205,11 +272,79 @@ func (b *Board) callLiar() error {
 		return errors.New("invalid status state: " + state.Status)
 	}
 
-	if state.CurrentAcctID != b.accountID {
+	if state.PlayerTurn != b.accountID {
+		return errors.New("not your turn")
+	}
+
+	if _, err := b.engine.Liar(b.lastState.GameID.String()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// odds shows the probability that the last bet is true.
+func (b *Board) odds() error {
+	odds, err := b.engine.Odds(b.lastState.GameID.String())
+	if err != nil {
+		return err
+	}
+
+	if odds.Bet == nil {
+		return errors.New("no bet has been made")
+	}
+
+	message := fmt.Sprintf("odds for bet %d x %d's: %.0f%% at least, %.0f%% exact", odds.Bet.Number, odds.Bet.Suit, odds.Bet.AtLeast*100, odds.Bet.Exactly*100)
+	b.printMessage(message, false)
+
+	return nil
+}
+
+// answerRematch accepts or declines playing the game again. The new game is
+// joined when the rematch event is received.
+func (b *Board) answerRematch(accept bool) error {
+	state, err := b.engine.QueryState(b.lastState.GameID.String())
+	if err != nil {
+		return err
+	}
+
+	if state.Status != "reconciled" {
+		return errors.New("invalid status state: " + state.Status)
+	}
+
+	rematch, err := b.engine.AnswerRematch(b.lastState.GameID.String(), accept)
+	if err != nil {
+		return err
+	}
+
+	if len(rematch.Pending) > 0 {
+		message := fmt.Sprintf("rematch: %d accepted, waiting on %d", len(rematch.Accepted), len(rematch.Pending))
+		b.printMessage(message, false)
+	}
+
+	return nil
+}
+
+// callExact calls the last bet exactly right.
+func (b *Board) callExact() error {
+	state, err := b.engine.QueryState(b.lastState.GameID.String())
+	if err != nil {
+		return err
+	}
+
+	if state.Status != "playing" {
+		return errors.New("invalid status state: " + state.Status)
+	}
+
+	if !state.Rules.SpotOn {
+		return errors.New("spot on calls are not allowed")
+	}
+
+	if state.PlayerTurn != b.accountID {
 		return errors.New("not your turn")
 	}
 
-	if _, err := b.engine.Liar(b.lastState.GameID); err != nil {
+	if _, err := b.engine.Exact(b.lastState.GameID.String()); err != nil {
 		return err
 	}
 
@@ -4,23 +4,16 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/ardanlabs/liarsdice/app/cli/liars/engine"
+	"github.com/ardanlabs/liarsdice/business/web/event"
 )
 
 // modalWinnerLoser shows the user if they won or lost.
-func (b *Board) modalWinnerLoser(win string, los string) (engine.State, error) {
-	state, err := b.engine.QueryState(b.lastState.GameID)
-	if err != nil {
-		return engine.State{}, err
-	}
-
-	if state.LastWinAcctID == b.accountID {
+func (b *Board) modalWinnerLoser(state event.State, win string, los string) {
+	if state.PlayerLastWin == b.accountID {
 		b.showModal(win)
-		return state, nil
+		return
 	}
 	b.showModal(los)
-
-	return state, nil
 }
 
 // showModal displays a modal dialog box.
@@ -70,8 +63,13 @@ func (b *Board) closeModal() {
 	b.modalMsg = ""
 	b.modalFn = nil
 
+	if b.replay != nil {
+		b.drawReplay()
+		return
+	}
+
 	active := false
-	if b.lastState.CurrentAcctID == b.accountID {
+	if b.lastState.PlayerTurn == b.accountID {
 		active = true
 	}
 
@@ -0,0 +1,229 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ardanlabs/liarsdice/app/cli/liars/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gdamore/tcell/v2"
+)
+
+// replay represents a finished game being played back. Every round starts
+// with the dice that were rolled, each step shows the next bet and the last
+// step of a round shows how the round ended.
+type replay struct {
+	doc   engine.Replay
+	steps []replayStep
+	step  int
+}
+
+// replayStep represents a position in the replay.
+type replayStep struct {
+	round  int  // The index of the round being played.
+	bets   int  // The number of bets made in the round.
+	result bool // The round has ended.
+}
+
+// Replay loads the specified finished game and displays its first round.
+// The arrow keys step through the game.
+func (b *Board) Replay(gameID string) error {
+	doc, err := b.engine.Replay(gameID)
+	if err != nil {
+		return err
+	}
+
+	if doc.Version != engine.ReplayVersion {
+		return fmt.Errorf("unsupported replay version %d, expecting %d", doc.Version, engine.ReplayVersion)
+	}
+
+	if len(doc.Rounds) == 0 {
+		return errors.New("game has no rounds to replay")
+	}
+
+	var steps []replayStep
+	for i, round := range doc.Rounds {
+		for bets := 0; bets <= len(round.Bets); bets++ {
+			steps = append(steps, replayStep{round: i, bets: bets})
+		}
+		steps = append(steps, replayStep{round: i, bets: len(round.Bets), result: true})
+	}
+
+	b.replay = &replay{
+		doc:   doc,
+		steps: steps,
+	}
+
+	b.drawReplay()
+
+	return nil
+}
+
+// stepReplay moves the replay a step with the left and right keys and a
+// round with the up and down keys.
+func (b *Board) stepReplay(key tcell.Key) error {
+	if b.replay == nil {
+		return nil
+	}
+
+	rp := b.replay
+	step := rp.step
+
+	switch key {
+	case tcell.KeyLeft:
+		step--
+
+	case tcell.KeyRight:
+		step++
+
+	case tcell.KeyUp:
+		round := rp.steps[step].round
+		if rp.steps[step].bets == 0 && !rp.steps[step].result {
+			round--
+		}
+		step = rp.roundStart(round)
+
+	case tcell.KeyDown:
+		step = rp.roundStart(rp.steps[step].round + 1)
+	}
+
+	if step < 0 || step >= len(rp.steps) {
+		b.screen.Beep()
+		return nil
+	}
+
+	rp.step = step
+	b.drawReplay()
+
+	return nil
+}
+
+// roundStart returns the first step of the specified round, or -1 if the
+// replay has no such round.
+func (rp *replay) roundStart(round int) int {
+	for i, step := range rp.steps {
+		if step.round == round {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// drawReplay displays the current step of the replay.
+func (b *Board) drawReplay() {
+	rp := b.replay
+	step := rp.steps[rp.step]
+	round := rp.doc.Rounds[step.round]
+	bets := round.Bets[:step.bets]
+
+	b.screen.Clear()
+	b.screen.HideCursor()
+
+	b.drawGameBox(true)
+
+	b.print(playersX, columnHeight, "Players:")
+	b.print(outX, columnHeight, "Outs:")
+	b.print(betX, columnHeight, "Last Bet:")
+	b.print(balX, columnHeight, "  Dice:")
+	b.print(anteX-6, anteY, "Ante:")
+	b.print(anteX, anteY, fmt.Sprintf("$%.2f", rp.doc.AnteUSD))
+
+	b.print(helpX, 1, "<left>/<right> : step back/forward")
+	b.print(helpX, 2, "<up>/<down>    : round back/forward")
+	b.print(helpX, 3, "<esc>          : quit")
+
+	b.print(helpX, statusY-6, "status   :")
+	b.print(helpX, statusY-5, "round    :")
+	b.print(helpX, statusY-4, "lastbet  :")
+	b.print(helpX, statusY-3, "lastwin  :")
+	b.print(helpX, statusY-2, "lastlose :")
+	b.print(helpX, statusY, "step     :")
+
+	b.print(helpX+11, statusY-6, fmt.Sprintf("replay     / %s", rp.doc.GameID))
+	roundStr := fmt.Sprintf("%d of %d", round.Round, rp.doc.Rounds[len(rp.doc.Rounds)-1].Round)
+	if round.Palifico {
+		roundStr += " palifico"
+	}
+	b.print(helpX+11, statusY-5, roundStr)
+	b.print(helpX+11, statusY, fmt.Sprintf("%d of %d", rp.step+1, len(rp.steps)))
+
+	if len(bets) > 0 {
+		bet := bets[len(bets)-1]
+		b.print(helpX+11, statusY-4, fmt.Sprintf("%d %s", bet.Number, words[bet.Suit]))
+	}
+
+	if step.result {
+		var empty common.Address
+		if round.Winner != empty {
+			b.print(helpX+11, statusY-3, b.fmtAddress(round.Winner))
+		}
+		b.print(helpX+11, statusY-2, b.fmtAddress(round.Loser))
+	}
+
+	// Print the player lines. Everyone's dice are shown in a replay.
+	for i, cup := range round.Cups {
+		addrY := columnHeight + 2 + i
+
+		switch {
+		case step.result && cup.Player == round.Loser:
+			b.print(playersX, addrY, " X")
+		case len(bets) > 0 && cup.Player == bets[len(bets)-1].Player:
+			b.print(playersX, addrY, "->")
+		}
+
+		b.print(playersX+3, addrY, b.fmtAddress(cup.Player))
+		b.print(outX, addrY, fmt.Sprintf("%d", cup.Outs))
+
+		for j := len(bets) - 1; j >= 0; j-- {
+			if bets[j].Player == cup.Player {
+				b.print(betX, addrY, fmt.Sprintf("%d %s", bets[j].Number, words[bets[j].Suit]))
+				break
+			}
+		}
+
+		var dice string
+		for _, d := range cup.Dice {
+			dice += fmt.Sprintf("%d", d)
+		}
+		b.print(balX+2, addrY, dice)
+	}
+
+	b.print(3, messageHeight+1, b.replayMessage(round, step))
+
+	if step.result && step.round == len(rp.doc.Rounds)-1 {
+		var empty common.Address
+		if rp.doc.Winner != empty {
+			b.print(3, messageHeight+2, fmt.Sprintf("%s won the game", b.fmtAddress(rp.doc.Winner)))
+		}
+	}
+
+	b.screen.Show()
+}
+
+// replayMessage describes what happened at the step of the round.
+func (b *Board) replayMessage(round engine.ReplayRound, step replayStep) string {
+	switch {
+	case step.result:
+		loser := b.fmtAddress(round.Loser)
+
+		switch round.Result {
+		case "liar":
+			return fmt.Sprintf("called liar with %d counted, %s loses", round.Total, loser)
+		case "exact":
+			return fmt.Sprintf("called exact with %d counted, %s loses", round.Total, loser)
+		case "forfeit":
+			return fmt.Sprintf("%s forfeits the turn", loser)
+		case "leave":
+			return fmt.Sprintf("%s left the game", loser)
+		default:
+			return fmt.Sprintf("%s is out", loser)
+		}
+
+	case step.bets > 0:
+		bet := round.Bets[step.bets-1]
+		return fmt.Sprintf("%s bets %d %s", b.fmtAddress(bet.Player), bet.Number, words[bet.Suit])
+	}
+
+	return fmt.Sprintf("round %d, the dice are rolled", round.Round)
+}
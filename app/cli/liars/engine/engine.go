@@ -8,20 +8,27 @@ import (
 	"fmt"
 	"io/fs"
 	"net/http"
+	"net/url"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/ardanlabs/ethereum"
+	"github.com/ardanlabs/liarsdice/business/core/game/fair"
+	"github.com/ardanlabs/liarsdice/business/web/event"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/websocket"
 )
 
 // Engine provides access to the game engine API.
 type Engine struct {
-	url   string
-	token string
+	url    string
+	token  string
+	socket *websocket.Conn
+	mu     sync.Mutex
 }
 
 // New constructs a client that provides access to the game engine.
@@ -89,8 +96,10 @@ func (e *Engine) Connect(keyStorePath string, address common.Address, passPhrase
 	return token, nil
 }
 
-// Events establishes a web socket connection to the game engine.
-func (e *Engine) Events(f func(event string, address common.Address)) (func(), error) {
+// Events establishes a web socket connection to the game engine. Problems
+// receiving an event, and chat messages that weren't sent, are reported to
+// the function as an error.
+func (e *Engine) Events(f func(env event.Envelope, err error)) (func(), error) {
 	url := strings.Replace(e.url, "http", "ws", 1)
 	url = fmt.Sprintf("%s/v1/game/events", url)
 
@@ -102,6 +111,11 @@ func (e *Engine) Events(f func(event string, address common.Address)) (func(), e
 		return nil, fmt.Errorf("dial: %w", err)
 	}
 
+	// Keep the socket so chat messages can be sent over it.
+	e.mu.Lock()
+	e.socket = socket
+	e.mu.Unlock()
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 
@@ -115,25 +129,80 @@ func (e *Engine) Events(f func(event string, address common.Address)) (func(), e
 		for {
 			_, message, err := socket.ReadMessage()
 			if err != nil {
-				f("error: "+err.Error()[:30], common.Address{})
+				f(event.Envelope{}, fmt.Errorf("read: %w", err))
 				return
 			}
 
-			var event struct {
-				Type    string         `json:"type"`
-				Address common.Address `json:"address"`
+			var env event.Envelope
+			if err := json.Unmarshal(message, &env); err != nil {
+				f(event.Envelope{}, fmt.Errorf("unmarshal: %w", err))
+				continue
+			}
+
+			if env.Version != event.Version {
+				f(env, fmt.Errorf("unsupported event version %d, expecting %d", env.Version, event.Version))
+				continue
 			}
-			if err := json.Unmarshal(message, &event); err != nil {
-				f("error: "+err.Error()[:30], common.Address{})
+
+			// A chat message that wasn't sent is only reported to the
+			// player who sent it.
+			if env.Type == event.TypeChatError {
+				var er ErrorResponse
+				if err := env.Decode(&er); err != nil {
+					f(env, err)
+					continue
+				}
+
+				f(env, errors.New("chat: "+er.message()))
 				continue
 			}
-			f(event.Type, event.Address)
+
+			f(env, nil)
 		}
 	}()
 
 	return teardown, nil
 }
 
+// SendChat sends a chat message to the table of the specified game over the
+// events web socket.
+func (e *Engine) SendChat(gameID string, message string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.socket == nil {
+		return errors.New("not connected to the game events")
+	}
+
+	chat := struct {
+		Type    string `json:"type"`
+		GameID  string `json:"gameID"`
+		Message string `json:"message"`
+	}{
+		Type:    event.TypeChat,
+		GameID:  gameID,
+		Message: message,
+	}
+
+	if err := e.socket.WriteJSON(chat); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+// Chat returns the chat messages kept for the table of the specified game.
+func (e *Engine) Chat(gameID string) ([]ChatMessage, error) {
+	url := fmt.Sprintf("%s/v1/game/%s/chat", e.url, gameID)
+
+	var msgs []ChatMessage
+	if err := e.do(url, &msgs, nil); err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
 // Configuration returns the configuration of the game engine.
 func (e *Engine) Configuration() (Config, error) {
 	url := fmt.Sprintf("%s/v1/game/config", e.url)
@@ -147,12 +216,12 @@ func (e *Engine) Configuration() (Config, error) {
 }
 
 // QueryState returns the current state of the specified game.
-func (e *Engine) QueryState(gameID string) (State, error) {
+func (e *Engine) QueryState(gameID string) (event.State, error) {
 	url := fmt.Sprintf("%s/v1/game/%s/state", e.url, gameID)
 
-	var state State
+	var state event.State
 	if err := e.do(url, &state, nil); err != nil {
-		return State{}, err
+		return event.State{}, err
 	}
 
 	return state, nil
@@ -170,90 +239,284 @@ func (e *Engine) Tables(gameID string) (Tables, error) {
 	return tables, nil
 }
 
-// NewGame starts a new game on the game engine.
-func (e *Engine) NewGame() (State, error) {
-	url := fmt.Sprintf("%s/v1/game/new", e.url)
+// NewGame starts a new game on the game engine using the specified settings.
+func (e *Engine) NewGame(settings Settings) (event.State, error) {
+	query := url.Values{}
+	query.Set("rules", settings.Ruleset)
 
-	var state State
+	if settings.AnteUSD > 0 {
+		query.Set("ante", strconv.FormatFloat(settings.AnteUSD, 'f', -1, 64))
+	}
+	if settings.MinPlayers > 0 {
+		query.Set("min", strconv.Itoa(settings.MinPlayers))
+	}
+	if settings.MaxPlayers > 0 {
+		query.Set("max", strconv.Itoa(settings.MaxPlayers))
+	}
+	if settings.TurnTimeout > 0 {
+		query.Set("timeout", settings.TurnTimeout.String())
+	}
+	if settings.Private {
+		query.Set("private", "true")
+	}
+	if len(settings.Allowlist) > 0 {
+		query.Set("allow", strings.Join(settings.Allowlist, ","))
+	}
+
+	url := fmt.Sprintf("%s/v1/game/new?%s", e.url, query.Encode())
+
+	var state event.State
 	if err := e.do(url, &state, nil); err != nil {
-		return State{}, err
+		return event.State{}, err
 	}
 
 	return state, nil
 }
 
+// Watch registers the account as a spectator of the specified game.
+func (e *Engine) Watch(gameID string) (event.State, error) {
+	url := fmt.Sprintf("%s/v1/game/%s/watch", e.url, gameID)
+
+	var state event.State
+	if err := e.do(url, &state, nil); err != nil {
+		return event.State{}, err
+	}
+
+	return state, nil
+}
+
+// Replay returns the replay document of the specified finished game.
+func (e *Engine) Replay(gameID string) (Replay, error) {
+	url := fmt.Sprintf("%s/v1/game/%s/replay", e.url, gameID)
+
+	var replay Replay
+	if err := e.do(url, &replay, nil); err != nil {
+		return Replay{}, err
+	}
+
+	return replay, nil
+}
+
 // StartGame generates the five dice for the player.
-func (e *Engine) StartGame(gameID string) (State, error) {
+func (e *Engine) StartGame(gameID string) (event.State, error) {
 	url := fmt.Sprintf("%s/v1/game/%s/start", e.url, gameID)
 
-	var state State
+	var state event.State
 	if err := e.do(url, &state, nil); err != nil {
-		return State{}, err
+		return event.State{}, err
 	}
 
 	return state, nil
 }
 
 // RollDice generates the five dice for the player.
-func (e *Engine) RollDice(gameID string) (State, error) {
+func (e *Engine) RollDice(gameID string) (event.State, error) {
 	url := fmt.Sprintf("%s/v1/game/%s/rolldice", e.url, gameID)
 
-	var state State
+	var state event.State
 	if err := e.do(url, &state, nil); err != nil {
-		return State{}, err
+		return event.State{}, err
 	}
 
 	return state, nil
 }
 
+// ClientSeed sets the seed to mix into the player's next roll.
+func (e *Engine) ClientSeed(gameID string, seed string) (event.State, error) {
+	url := fmt.Sprintf("%s/v1/game/%s/seed/%s", e.url, gameID, url.PathEscape(seed))
+
+	var state event.State
+	if err := e.do(url, &state, nil); err != nil {
+		return event.State{}, err
+	}
+
+	return state, nil
+}
+
+// Proof returns the revealed seeds and dice for the specified round.
+func (e *Engine) Proof(gameID string, round int) (Proof, error) {
+	url := fmt.Sprintf("%s/v1/game/%s/proof/%d", e.url, gameID, round)
+
+	var proof Proof
+	if err := e.do(url, &proof, nil); err != nil {
+		return Proof{}, err
+	}
+
+	return proof, nil
+}
+
+// VerifyRound retrieves the proof for the round captured by the specified
+// state and verifies the dice of every cup against the server seed hash and
+// commitments that were published while the round was being played.
+func (e *Engine) VerifyRound(state event.State) error {
+	proof, err := e.Proof(state.GameID.String(), state.Round)
+	if err != nil {
+		return fmt.Errorf("proof: %w", err)
+	}
+
+	if proof.ServerSeedHash != state.ServerSeedHash {
+		return fmt.Errorf("server seed hash changed during the round: published[%s] revealed[%s]", state.ServerSeedHash, proof.ServerSeedHash)
+	}
+
+	published := make(map[common.Address]string)
+	for _, cup := range state.Cups {
+		published[cup.Player] = cup.Commitment
+	}
+
+	for _, cup := range proof.Cups {
+		if commitment, exists := published[cup.AccountID]; exists && commitment != "" && commitment != cup.Commitment {
+			return fmt.Errorf("commitment changed during the round for player [%s]", cup.AccountID)
+		}
+
+		fc := fair.Cup{
+			Player:     cup.AccountID,
+			ClientSeed: cup.ClientSeed,
+			Dice:       cup.Dice,
+			Commitment: cup.Commitment,
+		}
+
+		if err := fair.Verify(state.GameID, proof.Round, proof.ServerSeed, proof.ServerSeedHash, fc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Odds returns the probability that the last bet is true based on the dice
+// in the player's cup.
+func (e *Engine) Odds(gameID string) (Odds, error) {
+	url := fmt.Sprintf("%s/v1/game/%s/odds", e.url, gameID)
+
+	var odds Odds
+	if err := e.do(url, &odds, nil); err != nil {
+		return Odds{}, err
+	}
+
+	return odds, nil
+}
+
 // JoinGame adds a player to the current game.
-func (e *Engine) JoinGame(gameID string) (State, error) {
+func (e *Engine) JoinGame(gameID string) (event.State, error) {
 	url := fmt.Sprintf("%s/v1/game/%s/join", e.url, gameID)
 
-	var state State
+	var state event.State
+	if err := e.do(url, &state, nil); err != nil {
+		return event.State{}, err
+	}
+
+	return state, nil
+}
+
+// JoinGameByCode adds a player to the private game the invite code was
+// created for.
+func (e *Engine) JoinGameByCode(code string) (event.State, error) {
+	gameID, _, found := strings.Cut(code, ".")
+	if !found {
+		return event.State{}, errors.New("invite code is malformed")
+	}
+
+	url := fmt.Sprintf("%s/v1/game/%s/join?code=%s", e.url, gameID, url.QueryEscape(code))
+
+	var state event.State
 	if err := e.do(url, &state, nil); err != nil {
-		return State{}, err
+		return event.State{}, err
 	}
 
 	return state, nil
 }
 
 // Bet submits a bet to the game engine.
-func (e *Engine) Bet(gameID string, number int, suit rune) (State, error) {
-	url := fmt.Sprintf("%s/v1/game/%s/bet/%d/%c", e.url, gameID, number, suit)
+func (e *Engine) Bet(gameID string, number int, suit int) (event.State, error) {
+	url := fmt.Sprintf("%s/v1/game/%s/bet/%d/%d", e.url, gameID, number, suit)
 
-	var state State
+	var state event.State
 	if err := e.do(url, &state, nil); err != nil {
-		return State{}, err
+		return event.State{}, err
 	}
 
 	return state, nil
 }
 
 // Liar submits a liar call to the game engine.
-func (e *Engine) Liar(gameID string) (State, error) {
+func (e *Engine) Liar(gameID string) (event.State, error) {
 	url := fmt.Sprintf("%s/v1/game/%s/liar", e.url, gameID)
 
-	var state State
+	var state event.State
+	if err := e.do(url, &state, nil); err != nil {
+		return event.State{}, err
+	}
+
+	return state, nil
+}
+
+// Exact submits a spot on call to the game engine.
+func (e *Engine) Exact(gameID string) (event.State, error) {
+	url := fmt.Sprintf("%s/v1/game/%s/exact", e.url, gameID)
+
+	var state event.State
 	if err := e.do(url, &state, nil); err != nil {
-		return State{}, err
+		return event.State{}, err
 	}
 
 	return state, nil
 }
 
 // Reconcile submits a reconcile call when the game is over.
-func (e *Engine) Reconcile(gameID string) (State, error) {
+func (e *Engine) Reconcile(gameID string) (event.State, error) {
 	url := fmt.Sprintf("%s/v1/game/%s/reconcile", e.url, gameID)
 
-	var state State
+	var state event.State
 	if err := e.do(url, &state, nil); err != nil {
-		return State{}, err
+		return event.State{}, err
 	}
 
 	return state, nil
 }
 
+// Rematch returns the answers the players have given to playing the game
+// again.
+func (e *Engine) Rematch(gameID string) (Rematch, error) {
+	url := fmt.Sprintf("%s/v1/game/%s/rematch", e.url, gameID)
+
+	var rematch Rematch
+	if err := e.do(url, &rematch, nil); err != nil {
+		return Rematch{}, err
+	}
+
+	return rematch, nil
+}
+
+// AnswerRematch accepts or declines playing the game again.
+func (e *Engine) AnswerRematch(gameID string, accept bool) (Rematch, error) {
+	answer := "decline"
+	if accept {
+		answer = "accept"
+	}
+
+	url := fmt.Sprintf("%s/v1/game/%s/rematch/%s", e.url, gameID, answer)
+
+	// The answer changes the game, so it's posted without a body.
+	var rematch Rematch
+	if err := e.do(url, &rematch, []byte{}); err != nil {
+		return Rematch{}, err
+	}
+
+	return rematch, nil
+}
+
+// Rating returns the rating of the specified player.
+func (e *Engine) Rating(address common.Address) (Rating, error) {
+	url := fmt.Sprintf("%s/v1/players/%s/rating", e.url, address)
+
+	var rating Rating
+	if err := e.do(url, &rating, nil); err != nil {
+		return Rating{}, err
+	}
+
+	return rating, nil
+}
+
 // do makes the actual http call to the engine.
 func (e *Engine) do(url string, result interface{}, input []byte) error {
 	var req *http.Request
@@ -283,7 +546,7 @@ func (e *Engine) do(url string, result interface{}, input []byte) error {
 		if err := json.NewDecoder(resp.Body).Decode(&er); err != nil {
 			return fmt.Errorf("status: %s, decode error: %w", resp.Status, err)
 		}
-		return fmt.Errorf("%s", er.Error)
+		return errors.New(er.message())
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
@@ -293,6 +556,22 @@ func (e *Engine) do(url string, result interface{}, input []byte) error {
 	return nil
 }
 
+// message returns the error of the response. The problem with each field that
+// failed validation is shown instead when there are any.
+func (er ErrorResponse) message() string {
+	if len(er.Fields) == 0 {
+		return er.Error
+	}
+
+	fields := make([]string, 0, len(er.Fields))
+	for field, msg := range er.Fields {
+		fields = append(fields, field+": "+msg)
+	}
+	sort.Strings(fields)
+
+	return strings.Join(fields, ", ")
+}
+
 // findKeyFile searches the keystore for the specified address key file.
 func findKeyFile(keyStorePath string, address common.Address) (string, error) {
 	keyStorePath = strings.TrimSuffix(keyStorePath, "/")
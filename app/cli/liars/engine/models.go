@@ -1,6 +1,11 @@
 package engine
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/web/event"
+	"github.com/ethereum/go-ethereum/common"
+)
 
 // ErrorResponse is the form used for API responses from failures in the API.
 type ErrorResponse struct {
@@ -21,37 +26,117 @@ type Token struct {
 	Address common.Address `json:"address"`
 }
 
-// State represents the game state.
-type State struct {
-	GameID        string           `json:"gameID"`
-	Status        string           `json:"status"`
-	AnteUSD       float64          `json:"anteUSD"`
-	LastOutAcctID common.Address   `json:"lastOut"`
-	LastWinAcctID common.Address   `json:"lastWin"`
-	CurrentAcctID common.Address   `json:"currentID"`
-	Round         int              `json:"round"`
-	Cups          []Cup            `json:"cups"`
-	CupsOrder     []common.Address `json:"playerOrder"`
-	Bets          []Bet            `json:"bets"`
-	Balances      []string         `json:"balances"`
+// Settings represents the settings a table is created with. Zero values
+// use the defaults of the game engine.
+type Settings struct {
+	Ruleset     string
+	AnteUSD     float64
+	MinPlayers  int
+	MaxPlayers  int
+	TurnTimeout time.Duration
+	Private     bool
+	Allowlist   []string
 }
 
-// Bet represents the bet response.
-type Bet struct {
-	AccountID common.Address `json:"account"`
-	Number    int            `json:"number"`
-	Suit      int            `json:"suit"`
+// Proof represents the revealed seeds and dice for a round.
+type Proof struct {
+	GameID         string     `json:"gameID"`
+	Round          int        `json:"round"`
+	ServerSeed     string     `json:"serverSeed"`
+	ServerSeedHash string     `json:"serverSeedHash"`
+	Cups           []ProofCup `json:"cups"`
 }
 
-// Cup represents the cup response.
-type Cup struct {
-	AccountID common.Address `json:"account"`
-	Dice      []int          `json:"dice"`
-	LastBet   Bet            `json:"lastBet"`
-	Outs      int            `json:"outs"`
+// ProofCup represents the revealed information for a single cup.
+type ProofCup struct {
+	AccountID  common.Address `json:"account"`
+	ClientSeed string         `json:"clientSeed"`
+	Dice       []int          `json:"dice"`
+	Commitment string         `json:"commitment"`
 }
 
 // Tables represents the current set of tables.
 type Tables struct {
 	GameIDs []string `json:"gameIDs"`
 }
+
+// Odds represents the probability of bets being true based on the dice in
+// the player's cup.
+type Odds struct {
+	GameID     string    `json:"gameID"`
+	Round      int       `json:"round"`
+	Dice       []int     `json:"dice"`
+	DiceInPlay int       `json:"diceInPlay"`
+	Wilds      bool      `json:"wilds"`
+	Bet        *BetOdds  `json:"bet"`
+	Suits      []BetOdds `json:"suits"`
+}
+
+// BetOdds represents the probability of a single bet being true.
+type BetOdds struct {
+	Number   int     `json:"number"`
+	Suit     int     `json:"suit"`
+	Known    int     `json:"known"`
+	Unknown  int     `json:"unknown"`
+	Expected float64 `json:"expected"`
+	AtLeast  float64 `json:"atLeast"`
+	Exactly  float64 `json:"exactly"`
+}
+
+// Rematch represents the answers the players have given to playing a game
+// again.
+type Rematch struct {
+	GameID    string           `json:"gameID"`
+	NewGameID string           `json:"newGameID"`
+	Accepted  []common.Address `json:"accepted"`
+	Declined  []common.Address `json:"declined"`
+	Pending   []common.Address `json:"pending"`
+}
+
+// Rating represents the rating of a player.
+type Rating struct {
+	Player common.Address `json:"player"`
+	Rating int            `json:"rating"`
+	Games  int            `json:"games"`
+	Wins   int            `json:"wins"`
+}
+
+// ReplayVersion represents the version of the replay document that can be
+// played back.
+const ReplayVersion = 1
+
+// Replay represents the play of a finished game round by round.
+type Replay struct {
+	Version     int              `json:"version"`
+	GameID      string           `json:"gameID"`
+	DateCreated string           `json:"dateCreated"`
+	AnteUSD     float64          `json:"anteUSD"`
+	Rules       event.Rules      `json:"rules"`
+	Status      string           `json:"status"`
+	Players     []common.Address `json:"players"`
+	Winner      common.Address   `json:"winner"`
+	Rounds      []ReplayRound    `json:"rounds"`
+}
+
+// ReplayRound represents the play of a single round.
+type ReplayRound struct {
+	Round          int            `json:"round"`
+	Palifico       bool           `json:"palifico"`
+	Cups           []event.Cup    `json:"cups"`
+	Bets           []event.Bet    `json:"bets"`
+	Result         string         `json:"result"`
+	Total          int            `json:"total"`
+	Winner         common.Address `json:"winner"`
+	Loser          common.Address `json:"loser"`
+	ServerSeed     string         `json:"serverSeed"`
+	ServerSeedHash string         `json:"serverSeedHash"`
+}
+
+// ChatMessage represents a message sent to the table of a game.
+type ChatMessage struct {
+	ID          string         `json:"id"`
+	GameID      string         `json:"gameID"`
+	AccountID   common.Address `json:"account"`
+	Message     string         `json:"message"`
+	DateCreated string         `json:"dateCreated"`
+}
@@ -49,7 +49,17 @@ func run() error {
 	// -------------------------------------------------------------------------
 	// Create the board and initialize the display.
 
-	board, err := board.New(eng, token.Address)
+	settings := engine.Settings{
+		Ruleset:     args.Ruleset,
+		AnteUSD:     args.AnteUSD,
+		MinPlayers:  args.MinPlayers,
+		MaxPlayers:  args.MaxPlayers,
+		TurnTimeout: args.TurnTimeout,
+		Private:     args.Private,
+		Allowlist:   args.Allowlist,
+	}
+
+	board, err := board.New(eng, token.Address, settings)
 	if err != nil {
 		return fmt.Errorf("new board: %w", err)
 	}
@@ -64,6 +74,33 @@ func run() error {
 	}
 	defer teardown()
 
+	// -------------------------------------------------------------------------
+	// Join a private game if an invite code was specified.
+
+	if args.Invite != "" {
+		if err := board.JoinByCode(args.Invite); err != nil {
+			return fmt.Errorf("join game: %w", err)
+		}
+	}
+
+	// -------------------------------------------------------------------------
+	// Watch a game as a spectator if one was specified.
+
+	if args.Watch != "" {
+		if err := board.Watch(args.Watch); err != nil {
+			return fmt.Errorf("watch game: %w", err)
+		}
+	}
+
+	// -------------------------------------------------------------------------
+	// Play back a finished game if one was specified.
+
+	if args.Replay != "" {
+		if err := board.Replay(args.Replay); err != nil {
+			return fmt.Errorf("replay game: %w", err)
+		}
+	}
+
 	// -------------------------------------------------------------------------
 	// Start handling board input.
 
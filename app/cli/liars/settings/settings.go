@@ -5,6 +5,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 )
 
 const usage = `
@@ -12,10 +14,26 @@ Usage:
 	liars
 	liars -a 0x8e113078adf6888b7ba84967f299f29aece24c55
 	liars -e http://0.0.0.0:3000 -a 0x0070742ff6003c3e809e78d524f0fe5dcc5ba7f7
+	liars -r perudo
+	liars -r perudo --ante 10 --max 3 --timeout 30s
+	liars -w 3e8ad3f5-a6a3-4cc8-a2a2-0c9ae8bb3e55
+	liars --replay 3e8ad3f5-a6a3-4cc8-a2a2-0c9ae8bb3e55
+	liars --private --allow 0x8e113078adf6888b7ba84967f299f29aece24c55
+	liars -i 3e8ad3f5-a6a3-4cc8-a2a2-0c9ae8bb3e55.Yl2hK8qzR3cM
 
 Options:
 	-e, --engine     The url of the game engine. Default: http://0.0.0.0:3000
 	-a, --account    The players account id. Default: 0x0070742ff6003c3e809e78d524f0fe5dcc5ba7f7
+	-r, --rules      The ruleset for new games (classic, perudo). Default: classic
+	--ante           The ante in USD for new games. Default: set by the engine
+	--min            The players required to start new games. Default: set by the engine
+	--max            The seats at new games. Default: set by the engine
+	--timeout        The turn timeout for new games. Default: set by the engine
+	--private        Hide new games from the lobby so players need an invite.
+	--allow          Comma separated accounts that can join new private games.
+	-i, --invite     The invite code of a private game to join.
+	-w, --watch      The id of a game to watch as a spectator.
+	--replay         The id of a finished game to play back with the arrow keys.
 `
 
 // PrintUsage displays the usage information.
@@ -28,8 +46,18 @@ type Flags map[string]struct{}
 
 // Args represents the values provided in the command line arguments.
 type Args struct {
-	Engine    string
-	AccountID string
+	Engine      string
+	AccountID   string
+	Ruleset     string
+	AnteUSD     float64
+	MinPlayers  int
+	MaxPlayers  int
+	TurnTimeout time.Duration
+	Private     bool
+	Allowlist   []string
+	Invite      string
+	Watch       string
+	Replay      string
 }
 
 // Parse will parse the command line flags. The command line flags will overwrite
@@ -38,6 +66,7 @@ func Parse() (Flags, Args, error) {
 	const (
 		engine    = "http://localhost:3000"
 		accountID = "0x0070742ff6003c3e809e78d524f0fe5dcc5ba7f7"
+		ruleset   = "classic"
 	)
 
 	flag.Usage = func() { fmt.Fprintf(os.Stderr, "%s\n", usage) }
@@ -45,6 +74,7 @@ func Parse() (Flags, Args, error) {
 	args := Args{
 		Engine:    engine,
 		AccountID: accountID,
+		Ruleset:   ruleset,
 	}
 	flags := parseCmdline(&args)
 
@@ -58,6 +88,22 @@ func parseCmdline(args *Args) Flags {
 	flag.StringVar(&args.Engine, "engine", args.Engine, "")
 	flag.StringVar(&args.AccountID, "a", args.AccountID, "")
 	flag.StringVar(&args.AccountID, "account", args.AccountID, "")
+	flag.StringVar(&args.Ruleset, "r", args.Ruleset, "")
+	flag.StringVar(&args.Ruleset, "rules", args.Ruleset, "")
+	flag.Float64Var(&args.AnteUSD, "ante", args.AnteUSD, "")
+	flag.IntVar(&args.MinPlayers, "min", args.MinPlayers, "")
+	flag.IntVar(&args.MaxPlayers, "max", args.MaxPlayers, "")
+	flag.DurationVar(&args.TurnTimeout, "timeout", args.TurnTimeout, "")
+	flag.BoolVar(&args.Private, "private", args.Private, "")
+	flag.Func("allow", "", func(v string) error {
+		args.Allowlist = append(args.Allowlist, strings.Split(v, ",")...)
+		return nil
+	})
+	flag.StringVar(&args.Invite, "i", args.Invite, "")
+	flag.StringVar(&args.Invite, "invite", args.Invite, "")
+	flag.StringVar(&args.Watch, "w", args.Watch, "")
+	flag.StringVar(&args.Watch, "watch", args.Watch, "")
+	flag.StringVar(&args.Replay, "replay", args.Replay, "")
 
 	flag.Bool("h", false, "show help usage")
 	flag.Bool("help", false, "show help usage")
@@ -4,6 +4,9 @@ package all
 import (
 	"github.com/ardanlabs/liarsdice/app/services/engine/handlers/checkgrp"
 	"github.com/ardanlabs/liarsdice/app/services/engine/handlers/gamegrp"
+	"github.com/ardanlabs/liarsdice/app/services/engine/handlers/playergrp"
+	"github.com/ardanlabs/liarsdice/app/services/engine/handlers/ratinggrp"
+	"github.com/ardanlabs/liarsdice/app/services/engine/handlers/tournamentgrp"
 	"github.com/ardanlabs/liarsdice/business/web/mux"
 	"github.com/ardanlabs/liarsdice/foundation/web"
 )
@@ -29,9 +32,32 @@ func (add) Add(app *web.App, cfg mux.Config) {
 		Converter:      cfg.Converter,
 		Bank:           cfg.Bank,
 		DB:             cfg.DB,
+		Bots:           cfg.Bots,
 		AnteUSD:        cfg.AnteUSD,
+		TurnTimeout:    cfg.TurnTimeout,
+		TimeoutAction:  cfg.TimeoutAction,
 		ActiveKID:      cfg.ActiveKID,
 		BankTimeout:    cfg.BankTimeout,
 		ConnectTimeout: cfg.ConnectTimeout,
+		InviteKey:      cfg.InviteKey,
+	})
+
+	playergrp.Routes(app, playergrp.Config{
+		Log:  cfg.Log,
+		Auth: cfg.Auth,
+		DB:   cfg.DB,
+	})
+
+	ratinggrp.Routes(app, ratinggrp.Config{
+		Log:     cfg.Log,
+		Auth:    cfg.Auth,
+		Ratings: cfg.Ratings,
+	})
+
+	tournamentgrp.Routes(app, tournamentgrp.Config{
+		Log:         cfg.Log,
+		Auth:        cfg.Auth,
+		Tournaments: cfg.Tournaments,
+		BankTimeout: cfg.BankTimeout,
 	})
 }
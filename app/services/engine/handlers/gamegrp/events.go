@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/ardanlabs/liarsdice/business/web/mid"
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ardanlabs/liarsdice/business/web/event"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
 )
 
@@ -15,6 +18,14 @@ import (
 type (
 	gameID   uuid.UUID
 	playerID string
+	role     string
+)
+
+// Represents the roles an account can have at a table. Spectators receive
+// the events for a game but don't take a seat.
+const (
+	rolePlayer    role = "player"
+	roleSpectator role = "spectator"
 )
 
 // evts maintains the set player channels for sending messages over
@@ -24,18 +35,29 @@ var evts = newEvents()
 // events maintains a mapping of unique id and channels so goroutines
 // can register and receive events.
 type events struct {
+	log     *logger.Logger
 	players map[playerID]chan string
-	games   map[gameID]map[playerID]struct{}
+	games   map[gameID]map[playerID]role
 	mu      sync.RWMutex
 }
 
 func newEvents() *events {
 	return &events{
 		players: make(map[playerID]chan string),
-		games:   make(map[gameID]map[playerID]struct{}),
+		games:   make(map[gameID]map[playerID]role),
 	}
 }
 
+// SetLogger sets the logger used to report the events that can't be sent
+// and the timeouts that can't be applied.
+// It's set before the handlers and workers that send events are started.
+func SetLogger(log *logger.Logger) {
+	evts.mu.Lock()
+	defer evts.mu.Unlock()
+
+	evts.log = log
+}
+
 func (evt *events) acquire(pID string) chan string {
 	evt.mu.Lock()
 	defer evt.mu.Unlock()
@@ -73,21 +95,15 @@ func (evt *events) release(pID string) error {
 	return nil
 }
 
-func (evt *events) numberOfPlayers(gID uuid.UUID) (int, error) {
-	evt.mu.RLock()
-	defer evt.mu.RUnlock()
-
-	gameID := gameID(gID)
-
-	playerMap, exists := evt.games[gameID]
-	if !exists {
-		return 0, fmt.Errorf("game id %q does not exist", gID)
-	}
+func (evt *events) addPlayerToGame(gID uuid.UUID, pID string) error {
+	return evt.addToGame(gID, pID, rolePlayer)
+}
 
-	return len(playerMap), nil
+func (evt *events) addSpectatorToGame(gID uuid.UUID, pID string) error {
+	return evt.addToGame(gID, pID, roleSpectator)
 }
 
-func (evt *events) addPlayerToGame(gID uuid.UUID, pID string) error {
+func (evt *events) addToGame(gID uuid.UUID, pID string, r role) error {
 	evt.mu.Lock()
 	defer evt.mu.Unlock()
 
@@ -100,16 +116,21 @@ func (evt *events) addPlayerToGame(gID uuid.UUID, pID string) error {
 
 	playerMap, exists := evt.games[gameID]
 	if !exists {
-		playerMap = make(map[playerID]struct{})
+		playerMap = make(map[playerID]role)
 		evt.games[gameID] = playerMap
 	}
 
-	playerMap[playID] = struct{}{}
+	// A player sitting at the table doesn't become a spectator.
+	if playerMap[playID] == rolePlayer {
+		return nil
+	}
+
+	playerMap[playID] = r
 
 	return nil
 }
 
-func (evt *events) removePlayersFromGame(gID uuid.UUID) error {
+func (evt *events) removePlayerFromGame(gID uuid.UUID, pID string) {
 	evt.mu.Lock()
 	defer evt.mu.Unlock()
 
@@ -117,53 +138,55 @@ func (evt *events) removePlayersFromGame(gID uuid.UUID) error {
 
 	playerMap, exists := evt.games[gameID]
 	if !exists {
-		return nil
+		return
 	}
 
-	for playID := range playerMap {
-		delete(playerMap, playID)
+	delete(playerMap, playerID(pID))
+	if len(playerMap) == 0 {
+		delete(evt.games, gameID)
 	}
-	delete(evt.games, gameID)
-
-	return nil
 }
 
-// send signals a message to every registered channel for the specified
-// game. Send will not block waiting for a receiver on any given channel.
-func (evt *events) send(ctx context.Context, gID uuid.UUID, typ string, v ...any) {
-	evt.mu.RLock()
-	defer evt.mu.RUnlock()
+func (evt *events) removePlayersFromGame(gID uuid.UUID) error {
+	evt.mu.Lock()
+	defer evt.mu.Unlock()
 
 	gameID := gameID(gID)
 
 	playerMap, exists := evt.games[gameID]
 	if !exists {
-		return
+		return nil
 	}
 
-	var msg string
-	switch v {
-	case nil:
-		msg = fmt.Sprintf(`{"type":"%s","address":"%s"}`, typ, mid.GetSubject(ctx))
+	for playID := range playerMap {
+		delete(playerMap, playID)
+	}
+	delete(evt.games, gameID)
 
-	default:
-		m := map[string]any{
-			"type":    typ,
-			"address": mid.GetSubject(ctx),
-		}
+	return nil
+}
 
-		for i := 0; i < len(v); i = i + 2 {
-			if vs, ok := v[i].(string); ok {
-				m[vs] = v[i+1]
-			}
+// sendState signals an event that changed the game to every registered
+// channel for the game. Each account receives the state of the game redacted
+// for them, so clients don't need to retrieve it again.
+func (evt *events) sendState(typ string, actor common.Address, state game.State) {
+	evt.send(typ, state.GameID, state.Sequence, actor, func(recipient common.Address) any {
+		return event.StatePayload{
+			State: toAppState(state, recipient),
 		}
+	})
+}
 
-		data, err := json.Marshal(m)
-		if err != nil {
-			return
-		}
+// send signals an event to every registered channel for the specified game.
+// The payload function is called for every account receiving the event. Send
+// will not block waiting for a receiver on any given channel.
+func (evt *events) send(typ string, gID uuid.UUID, sequence int, actor common.Address, payload func(recipient common.Address) any) {
+	evt.mu.RLock()
+	defer evt.mu.RUnlock()
 
-		msg = string(data)
+	playerMap, exists := evt.games[gameID(gID)]
+	if !exists {
+		return
 	}
 
 	for playID := range playerMap {
@@ -172,9 +195,63 @@ func (evt *events) send(ctx context.Context, gID uuid.UUID, typ string, v ...any
 			continue
 		}
 
+		msg, err := newMessage(typ, gID, sequence, actor, payload(common.HexToAddress(string(playID))))
+		if err != nil {
+			evt.logError("events.send", typ, gID, string(playID), err)
+			continue
+		}
+
 		select {
 		case ch <- msg:
 		default:
 		}
 	}
 }
+
+// sendPlayer signals an event to the registered channel of the specified
+// player only. SendPlayer will not block waiting for the receiver.
+func (evt *events) sendPlayer(pID string, typ string, gID uuid.UUID, actor common.Address, payload any) {
+	evt.mu.RLock()
+	defer evt.mu.RUnlock()
+
+	ch, exists := evt.players[playerID(pID)]
+	if !exists {
+		return
+	}
+
+	msg, err := newMessage(typ, gID, 0, actor, payload)
+	if err != nil {
+		evt.logError("events.sendplayer", typ, gID, pID, err)
+		return
+	}
+
+	select {
+	case ch <- msg:
+	default:
+	}
+}
+
+// logError reports an event that couldn't be sent to a player. The caller
+// must hold the lock.
+func (evt *events) logError(msg string, typ string, gID uuid.UUID, pID string, err error) {
+	if evt.log == nil {
+		return
+	}
+
+	evt.log.Error(context.Background(), msg, "type", typ, "game", gID, "player", pID, "ERROR", err)
+}
+
+// newMessage constructs the json message for an event.
+func newMessage(typ string, gID uuid.UUID, sequence int, actor common.Address, payload any) (string, error) {
+	env, err := event.New(typ, gID, sequence, actor, payload)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
@@ -3,10 +3,12 @@ package gamegrp
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,11 +17,15 @@ import (
 	"github.com/ardanlabs/ethereum"
 	"github.com/ardanlabs/ethereum/currency"
 	"github.com/ardanlabs/liarsdice/business/core/bank"
+	"github.com/ardanlabs/liarsdice/business/core/bot"
+	"github.com/ardanlabs/liarsdice/business/core/chat"
 	"github.com/ardanlabs/liarsdice/business/core/game"
 	"github.com/ardanlabs/liarsdice/business/web/auth"
 	"github.com/ardanlabs/liarsdice/business/web/errs"
+	"github.com/ardanlabs/liarsdice/business/web/event"
 	"github.com/ardanlabs/liarsdice/business/web/mid"
 	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ardanlabs/liarsdice/foundation/validate"
 	"github.com/ardanlabs/liarsdice/foundation/web"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/golang-jwt/jwt/v4"
@@ -27,17 +33,30 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// Represents the paging limits of the game history.
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
 type handlers struct {
 	converter      *currency.Converter
 	bank           *bank.Bank
+	banker         game.Banker
+	bots           *bot.Bots
 	storer         game.Storer
+	chat           *chat.Core
+	dicer          game.Dicer
 	log            *logger.Logger
 	ws             websocket.Upgrader
 	activeKID      string
 	auth           *auth.Auth
 	anteUSD        float64
+	turnTimeout    time.Duration
+	timeoutAction  string
 	bankTimeout    time.Duration
 	connectTimeout time.Duration
+	inviteKey      []byte
 }
 
 // connect is used to return a game token for API usage.
@@ -111,14 +130,18 @@ func (h *handlers) events(ctx context.Context, w http.ResponseWriter, r *http.Re
 	go func() {
 		defer wg.Done()
 
-		// This supports the ability to add a chat system and receive a client
-		// message.
+		// Chat messages are broadcast to the table. Problems with a message
+		// are only sent back to the player who sent it.
 		for {
-			message, p, err := c.ReadMessage()
+			_, p, err := c.ReadMessage()
 			if err != nil {
 				return
 			}
-			h.log.Info(ctx, "*********> socket read", "path", "/v1/game/events", "message", message, "p", string(p))
+
+			if err := h.chatMessage(ctx, p); err != nil {
+				h.log.Info(ctx, "websocket read", "path", "/v1/game/events", "ERROR", err)
+				evts.sendPlayer(subjectID, event.TypeChatError, uuid.Nil, common.HexToAddress(subjectID), toAppChatError(err))
+			}
 		}
 	}()
 
@@ -154,6 +177,92 @@ func (h *handlers) events(ctx context.Context, w http.ResponseWriter, r *http.Re
 	}
 }
 
+// chatMessage validates a chat message sent over the websocket and
+// broadcasts it to the table. Only the players seated at the table can chat.
+func (h *handlers) chatMessage(ctx context.Context, data []byte) error {
+	var nc appNewChat
+	if err := json.Unmarshal(data, &nc); err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to decode message: %w", err), http.StatusBadRequest)
+	}
+
+	if nc.Type != "chat" {
+		return errs.NewTrusted(fmt.Errorf("unknown message type %q", nc.Type), http.StatusBadRequest)
+	}
+
+	if err := nc.Validate(); err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	gameID, err := uuid.Parse(nc.GameID)
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
+	}
+
+	g, err := game.Tables.Retrieve(ctx, gameID)
+	if err != nil {
+		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
+	}
+
+	address := mid.GetSubject(ctx)
+
+	if _, exists := g.State().Cups[address]; !exists {
+		return errs.NewTrusted(fmt.Errorf("player [%s] does not exist in the game", address), http.StatusBadRequest)
+	}
+
+	msg, err := h.chat.Send(ctx, chat.NewMessage{
+		GameID: g.ID(),
+		Player: address,
+		Text:   nc.Message,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, chat.ErrRateLimited):
+			return errs.NewTrusted(err, http.StatusTooManyRequests)
+		case validate.IsFieldErrors(err):
+			return errs.NewTrusted(err, http.StatusBadRequest)
+		}
+		return fmt.Errorf("send: %w", err)
+	}
+
+	evts.send(event.TypeChat, g.ID(), g.State().Sequence, msg.Player, func(common.Address) any {
+		return toAppChat(msg)
+	})
+
+	return nil
+}
+
+// chatHistory returns the messages kept for the table of a game. The chat of
+// a private game is only shown to its players.
+func (h *handlers) chatHistory(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gameID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
+	}
+
+	g, err := game.Tables.Retrieve(ctx, gameID)
+	if err != nil {
+		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
+	}
+
+	state := g.State()
+
+	if _, seated := state.Cups[mid.GetSubject(ctx)]; state.Rules.Private && !seated {
+		return errs.NewTrusted(errors.New("game is private"), http.StatusForbidden)
+	}
+
+	msgs, err := h.chat.QueryByGame(ctx, gameID)
+	if err != nil {
+		return fmt.Errorf("query chat: %w", err)
+	}
+
+	chats := make([]appChat, len(msgs))
+	for i, msg := range msgs {
+		chats[i] = toAppChat(msg)
+	}
+
+	return web.Respond(ctx, w, chats, http.StatusOK)
+}
+
 // configuration returns the basic configuration the front end needs to use.
 func (h *handlers) configuration(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 
@@ -200,15 +309,191 @@ func (h *handlers) usd2Wei(ctx context.Context, w http.ResponseWriter, r *http.R
 
 // tables returns current set of existing tables.
 func (h *handlers) tables(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var ids []uuid.UUID
+	var tables []appTable
+	for _, gameID := range game.Tables.Active() {
+		g, err := game.Tables.Retrieve(ctx, gameID)
+		if err != nil {
+			continue
+		}
+
+		ids = append(ids, gameID)
+		tables = append(tables, toAppTable(g.State()))
+	}
+
 	info := struct {
 		GameIDs []uuid.UUID `json:"gameIDs"`
+		Tables  []appTable  `json:"tables"`
 	}{
-		GameIDs: game.Tables.Active(),
+		GameIDs: ids,
+		Tables:  tables,
 	}
 
 	return web.Respond(ctx, w, info, http.StatusOK)
 }
 
+// history returns a page of the past games that match the query, from
+// newest to oldest. The cursor returned continues the listing.
+func (h *handlers) history(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+
+	filter := game.QueryFilter{
+		Viewer: mid.GetSubject(ctx),
+	}
+
+	if v := query.Get("player"); v != "" {
+		if !common.IsHexAddress(v) {
+			return errs.NewTrusted(fmt.Errorf("invalid player address %q", v), http.StatusBadRequest)
+		}
+		filter.Player = common.HexToAddress(v)
+	}
+
+	if v := query.Get("status"); v != "" {
+		switch v {
+		case game.StatusNewGame, game.StatusPlaying, game.StatusGameOver, game.StatusReconciled, game.StatusAbandoned:
+		default:
+			return errs.NewTrusted(fmt.Errorf("invalid status %q", v), http.StatusBadRequest)
+		}
+		filter.Status = v
+	}
+
+	var err error
+
+	if v := query.Get("start"); v != "" {
+		if filter.StartDate, err = time.Parse(time.RFC3339, v); err != nil {
+			return errs.NewTrusted(fmt.Errorf("converting start date: %s", err), http.StatusBadRequest)
+		}
+	}
+
+	if v := query.Get("end"); v != "" {
+		if filter.EndDate, err = time.Parse(time.RFC3339, v); err != nil {
+			return errs.NewTrusted(fmt.Errorf("converting end date: %s", err), http.StatusBadRequest)
+		}
+	}
+
+	after, err := game.ParseCursor(query.Get("cursor"))
+	if err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	limit := defaultHistoryLimit
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxHistoryLimit {
+			return errs.NewTrusted(fmt.Errorf("invalid limit %q, must be between 1 and %d", v, maxHistoryLimit), http.StatusBadRequest)
+		}
+	}
+
+	sums, err := h.storer.QueryGames(ctx, filter, after, limit)
+	if err != nil {
+		return fmt.Errorf("query games: %w", err)
+	}
+
+	games := make([]appSummary, len(sums))
+	for i, s := range sums {
+		games[i] = toAppSummary(s)
+	}
+
+	// A full page means there could be more games to list.
+	var next string
+	if len(sums) == limit {
+		next = game.NewCursor(sums[len(sums)-1]).String()
+	}
+
+	info := struct {
+		Games  []appSummary `json:"games"`
+		Cursor string       `json:"cursor,omitempty"`
+	}{
+		Games:  games,
+		Cursor: next,
+	}
+
+	return web.Respond(ctx, w, info, http.StatusOK)
+}
+
+// rounds returns every round stored for a past game. The rounds of a private
+// game are only shown to its players.
+func (h *handlers) rounds(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gameID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
+	}
+
+	events, err := h.storer.QueryEvents(ctx, gameID)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return errs.NewTrusted(errors.New("no game exists"), http.StatusNotFound)
+		}
+		return fmt.Errorf("query events: %w", err)
+	}
+
+	state, err := game.Apply(events)
+	if err != nil {
+		return fmt.Errorf("apply: %w", err)
+	}
+
+	if _, seated := state.Cups[mid.GetSubject(ctx)]; state.Rules.Private && !seated {
+		return errs.NewTrusted(errors.New("game is private"), http.StatusForbidden)
+	}
+
+	states, err := h.storer.QueryRounds(ctx, gameID)
+	if err != nil {
+		return fmt.Errorf("query rounds: %w", err)
+	}
+
+	rounds := make([]appRound, len(states))
+	for i, state := range states {
+		rounds[i] = toAppRound(state)
+	}
+
+	info := struct {
+		GameID uuid.UUID  `json:"gameID"`
+		Rounds []appRound `json:"rounds"`
+	}{
+		GameID: gameID,
+		Rounds: rounds,
+	}
+
+	return web.Respond(ctx, w, info, http.StatusOK)
+}
+
+// replay returns the replay document of a finished game. The replay of a
+// private game is only shown to its players.
+func (h *handlers) replay(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gameID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
+	}
+
+	events, err := h.storer.QueryEvents(ctx, gameID)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return errs.NewTrusted(errors.New("no game exists"), http.StatusNotFound)
+		}
+		return fmt.Errorf("query events: %w", err)
+	}
+
+	rp, err := game.NewReplay(events)
+	if err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	if rp.Rules.Private {
+		var seated bool
+		for _, player := range rp.Players {
+			if player == mid.GetSubject(ctx) {
+				seated = true
+				break
+			}
+		}
+
+		if !seated {
+			return errs.NewTrusted(errors.New("game is private"), http.StatusForbidden)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppReplay(rp), http.StatusOK)
+}
+
 // state will return information about the game.
 func (h *handlers) state(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	claims := mid.GetClaims(ctx)
@@ -218,22 +503,112 @@ func (h *handlers) state(ctx context.Context, w http.ResponseWriter, r *http.Req
 		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
 	}
 
-	g, err := game.Tables.Retrieve(gameID)
+	g, err := game.Tables.Retrieve(ctx, gameID)
 	if err != nil {
-		resp := appState{
+		resp := event.State{
 			Status:  "nogame",
 			AnteUSD: h.anteUSD,
 		}
 		return web.Respond(ctx, w, resp, http.StatusOK)
 	}
 
-	return web.Respond(ctx, w, toAppState(g.State(), h.anteUSD, common.HexToAddress(claims.Subject)), http.StatusOK)
+	address := common.HexToAddress(claims.Subject)
+	state := g.State()
+
+	resp := toAppState(state, address)
+
+	// Only the creator of a private table is given the invite code.
+	if state.Rules.Private && len(state.ExistingPlayers) > 0 && state.ExistingPlayers[0] == address {
+		resp.InviteCode = game.InviteCode(h.inviteKey, g.ID())
+	}
+
+	return web.Respond(ctx, w, resp, http.StatusOK)
+}
+
+// watch registers the account as a spectator of the game. The state returned
+// doesn't show any dice until they are revealed at the end of a round.
+func (h *handlers) watch(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gameID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
+	}
+
+	g, err := game.Tables.Retrieve(ctx, gameID)
+	if err != nil {
+		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
+	}
+
+	subjectID := mid.GetSubject(ctx)
+
+	if !h.invited(g.State(), subjectID, r) {
+		return errs.NewTrusted(errors.New("game is private"), http.StatusForbidden)
+	}
+
+	if err := evts.addSpectatorToGame(g.ID(), subjectID.String()); err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to add spectator %q to game: %w", subjectID, err), http.StatusBadRequest)
+	}
+
+	var spectator common.Address
+	return web.Respond(ctx, w, toAppState(g.State(), spectator), http.StatusOK)
 }
 
 // newGame creates a new game if there is no game or the status of the current game
 // is GameOver.
 func (h *handlers) newGame(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	g, err := game.New(ctx, h.log, h.converter, h.storer, h.bank, mid.GetSubject(ctx), h.anteUSD)
+	rules, err := game.ParseRules(r.URL.Query().Get("rules"))
+	if err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	rules.TurnTimeout = h.turnTimeout
+	rules.TimeoutAction = h.timeoutAction
+
+	anteUSD := h.anteUSD
+
+	// The table creator can override the default settings of the engine.
+	query := r.URL.Query()
+
+	if v := query.Get("ante"); v != "" {
+		if anteUSD, err = strconv.ParseFloat(v, 64); err != nil {
+			return errs.NewTrusted(fmt.Errorf("converting ante: %s", err), http.StatusBadRequest)
+		}
+	}
+
+	if v := query.Get("min"); v != "" {
+		if rules.MinPlayers, err = strconv.Atoi(v); err != nil {
+			return errs.NewTrusted(fmt.Errorf("converting min players: %s", err), http.StatusBadRequest)
+		}
+	}
+
+	if v := query.Get("max"); v != "" {
+		if rules.MaxPlayers, err = strconv.Atoi(v); err != nil {
+			return errs.NewTrusted(fmt.Errorf("converting max players: %s", err), http.StatusBadRequest)
+		}
+	}
+
+	if v := query.Get("timeout"); v != "" {
+		if rules.TurnTimeout, err = time.ParseDuration(v); err != nil {
+			return errs.NewTrusted(fmt.Errorf("converting turn timeout: %s", err), http.StatusBadRequest)
+		}
+	}
+
+	if v := query.Get("private"); v != "" {
+		if rules.Private, err = strconv.ParseBool(v); err != nil {
+			return errs.NewTrusted(fmt.Errorf("converting private: %s", err), http.StatusBadRequest)
+		}
+	}
+
+	if v := query.Get("allow"); v != "" {
+		for _, address := range strings.Split(v, ",") {
+			if !common.IsHexAddress(address) {
+				return errs.NewTrusted(fmt.Errorf("invalid allowlist address %q", address), http.StatusBadRequest)
+			}
+			rules.Allowlist = append(rules.Allowlist, common.HexToAddress(address))
+		}
+		rules.Private = true
+	}
+
+	g, err := game.New(ctx, h.log, h.converter, h.storer, h.banker, h.dicer, mid.GetSubject(ctx), anteUSD, rules)
 	if err != nil {
 		return errs.NewTrusted(fmt.Errorf("unable to create game: %w", err), http.StatusBadRequest)
 	}
@@ -256,21 +631,26 @@ func (h *handlers) join(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
 	}
 
-	g, err := game.Tables.Retrieve(gameID)
+	g, err := game.Tables.Retrieve(ctx, gameID)
 	if err != nil {
 		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
 	}
 
-	n, err := evts.numberOfPlayers(g.ID())
-	if err != nil {
-		return fmt.Errorf("unable to determine number of players: %w", err)
-	}
+	subjectID := mid.GetSubject(ctx)
 
-	if n == 5 {
-		return errs.NewTrusted(errors.New("max players sitting"), http.StatusBadRequest)
+	if !h.invited(g.State(), subjectID, r) {
+		return errs.NewTrusted(errors.New("game is private, an invite code is required"), http.StatusForbidden)
 	}
 
-	subjectID := mid.GetSubject(ctx)
+	// The players of a tournament table are seated when the game is
+	// created, so joining only registers them for the game events.
+	if _, seated := g.State().Cups[subjectID]; seated && g.State().Rules.Tournament() {
+		if err := evts.addPlayerToGame(g.ID(), subjectID.String()); err != nil {
+			return errs.NewTrusted(fmt.Errorf("unable to add player %q to game: %w", subjectID, err), http.StatusBadRequest)
+		}
+
+		return h.state(ctx, w, r)
+	}
 
 	if err := g.AddAccount(ctx, subjectID); err != nil {
 		return errs.NewTrusted(err, http.StatusBadRequest)
@@ -280,11 +660,100 @@ func (h *handlers) join(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		return errs.NewTrusted(fmt.Errorf("unable to add player %q to game: %w", subjectID, err), http.StatusBadRequest)
 	}
 
-	evts.send(ctx, g.ID(), "join")
+	evts.sendState(event.TypeJoin, subjectID, g.State())
+
+	return h.state(ctx, w, r)
+}
+
+// leave removes the player from a game. Leaving a game that has started
+// forfeits the game.
+func (h *handlers) leave(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gameID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
+	}
+
+	g, err := game.Tables.Retrieve(ctx, gameID)
+	if err != nil {
+		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
+	}
+
+	subjectID := mid.GetSubject(ctx)
+
+	if err := g.Leave(ctx, subjectID); err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	evts.sendState(event.TypeLeave, subjectID, g.State())
+
+	evts.removePlayerFromGame(g.ID(), subjectID.String())
 
 	return h.state(ctx, w, r)
 }
 
+// addBots seats the specified number of bots at the table. Only the player
+// who created the table can add bots.
+func (h *handlers) addBots(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gameID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
+	}
+
+	g, err := game.Tables.Retrieve(ctx, gameID)
+	if err != nil {
+		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
+	}
+
+	number, err := strconv.Atoi(web.Param(r, "number"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("converting number: %s", err), http.StatusBadRequest)
+	}
+
+	state := g.State()
+
+	if len(state.ExistingPlayers) == 0 || state.ExistingPlayers[0] != mid.GetSubject(ctx) {
+		return errs.NewTrusted(errors.New("only the table creator can add bots"), http.StatusForbidden)
+	}
+
+	if len(state.ExistingPlayers)+number > state.Rules.MaxPlayers {
+		return errs.NewTrusted(fmt.Errorf("max players sitting: max[%d]", state.Rules.MaxPlayers), http.StatusBadRequest)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.bankTimeout)
+	defer cancel()
+
+	players, err := h.bots.Add(ctx, g, number, r.URL.Query().Get("strategy"))
+	for _, player := range players {
+		evts.sendState(event.TypeJoin, player, g.State())
+	}
+
+	if err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	return h.state(ctx, w, r)
+}
+
+// invited reports if the player can join the game. A private game can be
+// joined by the players on the allowlist, the players already seated, or
+// with an invite code for the game.
+func (h *handlers) invited(state game.State, player common.Address, r *http.Request) bool {
+	if state.Rules.Allowed(player) {
+		return true
+	}
+
+	if _, exists := state.Cups[player]; exists {
+		return true
+	}
+
+	gameID, err := game.ParseInviteCode(h.inviteKey, r.URL.Query().Get("code"))
+	if err != nil {
+		return false
+	}
+
+	return gameID == state.GameID
+}
+
 // startGame changes the status of the game so players can begin to play.
 func (h *handlers) startGame(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	gameID, err := uuid.Parse(web.Param(r, "id"))
@@ -292,7 +761,7 @@ func (h *handlers) startGame(ctx context.Context, w http.ResponseWriter, r *http
 		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
 	}
 
-	g, err := game.Tables.Retrieve(gameID)
+	g, err := game.Tables.Retrieve(ctx, gameID)
 	if err != nil {
 		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
 	}
@@ -301,7 +770,9 @@ func (h *handlers) startGame(ctx context.Context, w http.ResponseWriter, r *http
 		return errs.NewTrusted(err, http.StatusBadRequest)
 	}
 
-	evts.send(ctx, g.ID(), "start")
+	evts.sendState(event.TypeStart, mid.GetSubject(ctx), g.State())
+
+	h.bots.Roll(ctx, g, BotAction)
 
 	return h.state(ctx, w, r)
 }
@@ -313,7 +784,7 @@ func (h *handlers) rollDice(ctx context.Context, w http.ResponseWriter, r *http.
 		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
 	}
 
-	g, err := game.Tables.Retrieve(gameID)
+	g, err := game.Tables.Retrieve(ctx, gameID)
 	if err != nil {
 		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
 	}
@@ -322,40 +793,146 @@ func (h *handlers) rollDice(ctx context.Context, w http.ResponseWriter, r *http.
 		return errs.NewTrusted(err, http.StatusBadRequest)
 	}
 
-	evts.send(ctx, g.ID(), "rolldice")
+	evts.sendState(event.TypeRollDice, mid.GetSubject(ctx), g.State())
 
 	return h.state(ctx, w, r)
 }
 
-// bet processes a bet made by a player in a game.
-func (h *handlers) bet(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+// clientSeed sets the seed the player wants mixed into their next roll.
+func (h *handlers) clientSeed(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	gameID, err := uuid.Parse(web.Param(r, "id"))
 	if err != nil {
 		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
 	}
 
-	g, err := game.Tables.Retrieve(gameID)
+	g, err := game.Tables.Retrieve(ctx, gameID)
 	if err != nil {
 		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
 	}
 
-	number, err := strconv.Atoi(web.Param(r, "number"))
+	if err := g.SetClientSeed(ctx, mid.GetSubject(ctx), web.Param(r, "seed")); err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	return h.state(ctx, w, r)
+}
+
+// proof returns the revealed seeds and dice for a round that is over so
+// the roll can be verified.
+func (h *handlers) proof(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gameID, err := uuid.Parse(web.Param(r, "id"))
 	if err != nil {
-		return errs.NewTrusted(fmt.Errorf("converting number: %s", err), http.StatusBadRequest)
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
+	}
+
+	g, err := game.Tables.Retrieve(ctx, gameID)
+	if err != nil {
+		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
+	}
+
+	round, err := strconv.Atoi(web.Param(r, "round"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("converting round: %s", err), http.StatusBadRequest)
+	}
+
+	proof, err := g.Proof(round)
+	if err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	return web.Respond(ctx, w, toAppProof(g.ID(), proof), http.StatusOK)
+}
+
+// odds returns the probability that a bet is true based on the dice in the
+// caller's cup. The last bet is used unless a number and suit are provided.
+func (h *handlers) odds(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gameID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
+	}
+
+	g, err := game.Tables.Retrieve(ctx, gameID)
+	if err != nil {
+		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
+	}
+
+	address := mid.GetSubject(ctx)
+	state := g.State()
+
+	if _, exists := state.Cups[address]; !exists {
+		return errs.NewTrusted(fmt.Errorf("player [%s] does not exist in the game", address), http.StatusBadRequest)
+	}
+
+	number, suit, err := oddsBet(state, r.URL.Query())
+	if err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	return web.Respond(ctx, w, toAppOdds(state, address, number, suit), http.StatusOK)
+}
+
+// oddsBet returns the bet to calculate the odds for. The bet in the query is
+// used when provided, otherwise the last bet of the round. Before the first
+// bet of the round, the smallest opening bet is used.
+func oddsBet(state game.State, query url.Values) (number int, suit int, err error) {
+	if v := query.Get("number"); v != "" {
+		if number, err = strconv.Atoi(v); err != nil {
+			return 0, 0, fmt.Errorf("converting number: %s", err)
+		}
+
+		if suit, err = strconv.Atoi(query.Get("suit")); err != nil {
+			return 0, 0, fmt.Errorf("converting suit: %s", err)
+		}
+
+		if number < 1 {
+			return 0, 0, fmt.Errorf("number must be 1 or greater: number[%d]", number)
+		}
+
+		if suit < 1 || suit > game.NumberOfSuits {
+			return 0, 0, fmt.Errorf("suit must be between 1 and %d: suit[%d]", game.NumberOfSuits, suit)
+		}
+
+		return number, suit, nil
+	}
+
+	if len(state.Bets) > 0 {
+		lastBet := state.Bets[len(state.Bets)-1]
+		return lastBet.Number, lastBet.Suit, nil
+	}
+
+	number, suit, ok := state.MinimumBet()
+	if !ok {
+		return 0, 0, errors.New("no bet can be made with the dice in play")
+	}
+
+	return number, suit, nil
+}
+
+// bet processes a bet made by a player in a game.
+func (h *handlers) bet(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gameID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
 	}
 
-	suit, err := strconv.Atoi(web.Param(r, "suit"))
+	g, err := game.Tables.Retrieve(ctx, gameID)
 	if err != nil {
-		return errs.NewTrusted(fmt.Errorf("converting suit: %s", err), http.StatusBadRequest)
+		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
+	}
+
+	nb, err := toAppNewBet(web.Param(r, "number"), web.Param(r, "suit"))
+	if err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
 	}
 
 	address := mid.GetSubject(ctx)
 
-	if err := g.Bet(ctx, address, number, suit); err != nil {
+	// The field errors of an invalid bet are returned to the player.
+	if err := g.Bet(ctx, address, nb.Number, nb.Suit); err != nil {
 		return errs.NewTrusted(err, http.StatusBadRequest)
 	}
 
-	evts.send(ctx, g.ID(), "bet", "index", g.State().Cups[address].OrderIdx)
+	evts.sendState(event.TypeBet, address, g.State())
 
 	return h.state(ctx, w, r)
 }
@@ -367,7 +944,7 @@ func (h *handlers) callLiar(ctx context.Context, w http.ResponseWriter, r *http.
 		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
 	}
 
-	g, err := game.Tables.Retrieve(gameID)
+	g, err := game.Tables.Retrieve(ctx, gameID)
 	if err != nil {
 		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
 	}
@@ -376,11 +953,45 @@ func (h *handlers) callLiar(ctx context.Context, w http.ResponseWriter, r *http.
 		return errs.NewTrusted(err, http.StatusBadRequest)
 	}
 
-	if _, err := g.NextRound(ctx); err != nil {
+	// The reveal of the cups is kept in the state of the next round. A player
+	// leaving in the meantime could have ended the game.
+	if g.State().Status == game.StatusRoundOver {
+		if _, err := g.NextRound(ctx); err != nil {
+			return errs.NewTrusted(err, http.StatusBadRequest)
+		}
+	}
+
+	evts.sendState(event.TypeCallLiar, mid.GetSubject(ctx), g.State())
+
+	return h.state(ctx, w, r)
+}
+
+// callExact processes a spot on claim and defines a winner and a loser for
+// the round.
+func (h *handlers) callExact(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gameID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
+	}
+
+	g, err := game.Tables.Retrieve(ctx, gameID)
+	if err != nil {
+		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
+	}
+
+	if _, _, err := g.CallExact(ctx, mid.GetSubject(ctx)); err != nil {
 		return errs.NewTrusted(err, http.StatusBadRequest)
 	}
 
-	evts.send(ctx, g.ID(), "callliar")
+	// The reveal of the cups is kept in the state of the next round. A player
+	// leaving in the meantime could have ended the game.
+	if g.State().Status == game.StatusRoundOver {
+		if _, err := g.NextRound(ctx); err != nil {
+			return errs.NewTrusted(err, http.StatusBadRequest)
+		}
+	}
+
+	evts.sendState(event.TypeCallExact, mid.GetSubject(ctx), g.State())
 
 	return h.state(ctx, w, r)
 }
@@ -392,7 +1003,7 @@ func (h *handlers) reconcile(ctx context.Context, w http.ResponseWriter, r *http
 		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
 	}
 
-	g, err := game.Tables.Retrieve(gameID)
+	g, err := game.Tables.Retrieve(ctx, gameID)
 	if err != nil {
 		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
 	}
@@ -400,85 +1011,203 @@ func (h *handlers) reconcile(ctx context.Context, w http.ResponseWriter, r *http
 	ctx, cancel := context.WithTimeout(ctx, h.bankTimeout)
 	defer cancel()
 
-	if _, _, err := g.Reconcile(ctx); err != nil {
+	if err := reconcile(ctx, g, mid.GetSubject(ctx)); err != nil {
 		return errs.NewTrusted(err, http.StatusInternalServerError)
 	}
 
-	evts.send(ctx, g.ID(), "reconcile")
+	return h.state(ctx, w, r)
+}
+
+// reconcile settles a game that is over and tells the players about it. It's
+// used by the handler and when the scheduler ended the game.
+func reconcile(ctx context.Context, g *game.Game, subjectID common.Address) error {
+	if _, _, err := g.Reconcile(ctx); err != nil {
+		return err
+	}
+
+	evts.sendState(event.TypeReconcile, subjectID, g.State())
 
 	evts.removePlayersFromGame(g.ID())
 
-	return h.state(ctx, w, r)
+	return nil
 }
 
-// balance returns the player balance from the smart contract.
-func (h *handlers) balance(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	ctx, cancel := context.WithTimeout(ctx, h.bankTimeout)
-	defer cancel()
-
-	balanceGWei, err := h.bank.AccountBalance(ctx, mid.GetSubject(ctx))
+// rematch returns the answers the players have given to a rematch.
+func (h *handlers) rematch(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gameID, err := uuid.Parse(web.Param(r, "id"))
 	if err != nil {
-		return errs.NewTrusted(err, http.StatusInternalServerError)
+		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
 	}
 
-	resp := struct {
-		Balance string `json:"balance"`
-	}{
-		Balance: h.converter.GWei2USD(balanceGWei),
+	g, err := game.Tables.Retrieve(ctx, gameID)
+	if err != nil {
+		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
 	}
 
-	return web.Respond(ctx, w, resp, http.StatusOK)
+	return web.Respond(ctx, w, toAppRematch(g.Rematch()), http.StatusOK)
 }
 
-// nextTurn changes the account that will make the next move.
-func (h *handlers) nextTurn(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+// answerRematch records if the player accepts playing the reconciled game
+// again. Once every player has answered, a new game is started with the
+// players who accepted. Bots don't play in a rematch.
+func (h *handlers) answerRematch(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	gameID, err := uuid.Parse(web.Param(r, "id"))
 	if err != nil {
 		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
 	}
 
-	g, err := game.Tables.Retrieve(gameID)
+	g, err := game.Tables.Retrieve(ctx, gameID)
 	if err != nil {
 		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
 	}
 
-	if err := g.NextTurn(ctx); err != nil {
+	subjectID := mid.GetSubject(ctx)
+	accept := strings.HasSuffix(r.URL.Path, "/accept")
+
+	rm, err := g.AnswerRematch(ctx, subjectID, accept)
+	if err != nil {
 		return errs.NewTrusted(err, http.StatusBadRequest)
 	}
 
-	evts.send(ctx, g.ID(), "nextturn")
+	state := g.State()
+	for _, player := range rm.Pending {
+		if h.bots.Seated(state, player) {
+			if rm, err = g.AnswerRematch(ctx, player, false); err != nil {
+				return errs.NewTrusted(err, http.StatusBadRequest)
+			}
+		}
+	}
 
-	return h.state(ctx, w, r)
+	// The players were removed from the game's events when it reconciled,
+	// so players who accept need to be added back to hear about the rematch.
+	if accept {
+		if err := evts.addPlayerToGame(g.ID(), subjectID.String()); err != nil {
+			return errs.NewTrusted(fmt.Errorf("unable to add player %q to game: %w", subjectID, err), http.StatusBadRequest)
+		}
+	}
+
+	if len(rm.Pending) > 0 {
+		evts.sendState(event.TypeRematch, subjectID, g.State())
+		return web.Respond(ctx, w, toAppRematch(rm), http.StatusOK)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.bankTimeout)
+	defer cancel()
+
+	if err := startRematch(ctx, g, subjectID); err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	return web.Respond(ctx, w, toAppRematch(g.Rematch()), http.StatusOK)
 }
 
-// updateOut replaces the current out amount of the player. This call is not
-// part of the game flow, it is used to control when a player should be removed
-// from the game.
-func (h *handlers) updateOut(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	gameID, err := uuid.Parse(web.Param(r, "id"))
+// startRematch starts a new game with the players who accepted the rematch
+// and tells the players about it. It's used once every player answered or
+// the scheduler declined the players who didn't answer in time.
+func startRematch(ctx context.Context, g *game.Game, subjectID common.Address) error {
+	ng, err := g.StartRematch(ctx)
 	if err != nil {
-		return errs.NewTrusted(fmt.Errorf("unable to parse game id: %w", err), http.StatusBadRequest)
+		evts.sendState(event.TypeRematch, subjectID, g.State())
+		evts.removePlayersFromGame(g.ID())
+		return err
 	}
 
-	g, err := game.Tables.Retrieve(gameID)
+	for _, player := range g.Rematch().Accepted {
+		evts.addPlayerToGame(ng.ID(), player.String())
+	}
+
+	// Players learn the id of the new game from the rematch before they are
+	// told the new game has started.
+	evts.sendState(event.TypeRematch, subjectID, g.State())
+	evts.removePlayersFromGame(g.ID())
+	evts.sendState(event.TypeStart, subjectID, ng.State())
+
+	return nil
+}
+
+// Timeout sends an event to the players of a game when the scheduler expired
+// a player's turn. A game the timeout ended is settled like it is by the
+// handlers. When the rematch expired, it's started with the players who
+// accepted.
+func Timeout(ctx context.Context, t game.Timeout) {
+	g, err := game.Tables.Retrieve(ctx, t.GameID)
 	if err != nil {
-		return errs.NewTrusted(errors.New("no game exists"), http.StatusBadRequest)
+		return
 	}
 
-	outs, err := strconv.Atoi(web.Param(r, "outs"))
+	if t.Action == game.TimeoutRematch {
+		if err := startRematch(ctx, g, t.Player); err != nil {
+			logTimeoutError(ctx, "gamegrp.timeout.rematch", g.ID(), err)
+		}
+		return
+	}
+
+	state := g.State()
+
+	evts.send(event.TypeTimeout, state.GameID, state.Sequence, t.Player, func(recipient common.Address) any {
+		return event.StatePayload{
+			State:  toAppState(state, recipient),
+			Action: t.Action,
+		}
+	})
+
+	// The players learn the game is over from the timeout before the game
+	// is settled.
+	if state.Status == game.StatusGameOver {
+		if err := reconcile(ctx, g, state.PlayerLastWin); err != nil {
+			logTimeoutError(ctx, "gamegrp.timeout.reconcile", g.ID(), err)
+		}
+	}
+}
+
+// logTimeoutError reports a timeout that couldn't be applied to a game.
+func logTimeoutError(ctx context.Context, msg string, gameID uuid.UUID, err error) {
+	evts.mu.RLock()
+	defer evts.mu.RUnlock()
+
+	if evts.log == nil {
+		return
+	}
+
+	evts.log.Error(ctx, msg, "id", gameID, "ERROR", err)
+}
+
+// BotAction sends an event to the players of a game when a bot played.
+func BotAction(ctx context.Context, a bot.Action) {
+	g, err := game.Tables.Retrieve(ctx, a.GameID)
 	if err != nil {
-		return errs.NewTrusted(fmt.Errorf("converting outs: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// The types of the bot actions are the types of the events.
+	evts.sendState(a.Type, a.Player, g.State())
+
+	if a.Type == bot.ActionReconcile {
+		evts.removePlayersFromGame(a.GameID)
 	}
+}
+
+// balance returns the player balance from the smart contract.
+func (h *handlers) balance(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, h.bankTimeout)
+	defer cancel()
 
 	address := mid.GetSubject(ctx)
 
-	if err := g.ApplyOut(ctx, address, outs); err != nil {
-		return errs.NewTrusted(err, http.StatusBadRequest)
+	balanceGWei, err := h.bank.AccountBalance(ctx, address)
+	if err != nil {
+		return errs.NewTrusted(err, http.StatusInternalServerError)
 	}
 
-	evts.send(ctx, g.ID(), "outs")
+	resp := struct {
+		Balance   string `json:"balance"`
+		Available string `json:"available"`
+	}{
+		Balance:   h.converter.GWei2USD(balanceGWei),
+		Available: h.converter.GWei2USD(game.Escrow.Available(address, balanceGWei)),
+	}
 
-	return h.state(ctx, w, r)
+	return web.Respond(ctx, w, resp, http.StatusOK)
 }
 
 func validateSignature(ctx context.Context, log *logger.Logger, r *http.Request, timeout time.Duration, chainID int) (string, error) {
@@ -0,0 +1,73 @@
+package gamegrp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_OddsBet(t *testing.T) {
+	player1 := common.HexToAddress("0x8e113078adf6888b7ba84967f299f29aece24c55")
+	player2 := common.HexToAddress("0x0070742ff6003c3e809e78d524f0fe5dcc5ba7f7")
+
+	rules, err := game.ParseRules(game.RulesetClassic)
+	if err != nil {
+		t.Fatalf("unexpected error parsing the rules: %s", err)
+	}
+
+	state := game.State{
+		Rules: rules,
+		Cups: map[common.Address]game.Cup{
+			player1: {Player: player1, Dice: []int{1, 2, 3, 4, 5}},
+			player2: {Player: player2, Dice: []int{6, 6, 6, 2, 2}},
+		},
+	}
+
+	// Before the first bet, the odds are for the smallest opening bet.
+	number, suit, err := oddsBet(state, url.Values{})
+	if err != nil {
+		t.Fatalf("unexpected error with no bets: %s", err)
+	}
+
+	if number < 1 || suit < 1 {
+		t.Fatalf("expecting a real bet with no bets; got number %d suit %d", number, suit)
+	}
+
+	if err := state.CheckBet(number, suit); err != nil {
+		t.Fatalf("expecting the opening bet to be legal: %s", err)
+	}
+
+	// Once a bet is made, the odds are for the last bet.
+	state.Bets = []game.Bet{{Player: player1, Number: 3, Suit: 6}}
+
+	if number, suit, err = oddsBet(state, url.Values{}); err != nil || number != 3 || suit != 6 {
+		t.Fatalf("expecting the last bet 3x6; got number %d suit %d err %v", number, suit, err)
+	}
+
+	// A bet in the query must be a real bet.
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{name: "number zero", query: url.Values{"number": {"0"}, "suit": {"4"}}},
+		{name: "suit zero", query: url.Values{"number": {"2"}, "suit": {"0"}}},
+		{name: "suit seven", query: url.Values{"number": {"2"}, "suit": {"7"}}},
+		{name: "suit missing", query: url.Values{"number": {"2"}}},
+	}
+
+	for _, tt := range tests {
+		if _, _, err := oddsBet(state, tt.query); err == nil {
+			t.Fatalf("%s: expecting an error", tt.name)
+		}
+	}
+
+	// Without dice in play no bet can be made.
+	state.Bets = nil
+	state.Cups = nil
+
+	if _, _, err := oddsBet(state, url.Values{}); err == nil {
+		t.Fatal("expecting an error with no dice in play")
+	}
+}
@@ -1,43 +1,34 @@
 package gamegrp
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/ardanlabs/liarsdice/business/core/chat"
 	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ardanlabs/liarsdice/business/core/game/odds"
+	"github.com/ardanlabs/liarsdice/business/web/errs"
+	"github.com/ardanlabs/liarsdice/business/web/event"
+	"github.com/ardanlabs/liarsdice/foundation/validate"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
 )
 
-type appState struct {
-	GameID          uuid.UUID        `json:"gameID"`
-	GameName        string           `json:"gameName"`
-	DateCreated     string           `json:"dateCreated"`
-	AnteUSD         float64          `json:"anteUSD"`
-	Status          string           `json:"status"`
-	PlayerLastOut   common.Address   `json:"lastOut"`
-	PlayerLastWin   common.Address   `json:"lastWin"`
-	PlayerTurn      common.Address   `json:"currentID"`
-	Round           int              `json:"round"`
-	Cups            []appCup         `json:"cups"`
-	ExistingPlayers []common.Address `json:"playerOrder"`
-	Bets            []appBet         `json:"bets"`
-	Balances        []string         `json:"balances"`
-}
-
-func toAppState(state game.State, anteUSD float64, address common.Address) appState {
-	var cups []appCup
+func toAppState(state game.State, address common.Address) event.State {
+	var cups []event.Cup
 	for _, accountID := range state.ExistingPlayers {
 		cup := state.Cups[accountID]
 
 		// Don't share the dice information for other players.
-		dice := []int{0, 0, 0, 0, 0}
+		dice := make([]int, len(cup.Dice))
 		if accountID == address {
 			dice = cup.Dice
 		}
 		cups = append(cups, toAppCup(cup, dice))
 	}
 
-	var bets []appBet
+	var bets []event.Bet
 	for _, bet := range state.Bets {
 		bets = append(bets, toAppBet(bet))
 	}
@@ -47,48 +38,474 @@ func toAppState(state game.State, anteUSD float64, address common.Address) appSt
 		balances = append(balances, balance.Amount)
 	}
 
-	return appState{
-		GameID:          state.GameID,
-		GameName:        state.GameName,
-		DateCreated:     state.DateCreated.Format(time.RFC3339),
-		AnteUSD:         anteUSD,
-		Status:          state.Status,
-		PlayerLastOut:   state.PlayerLastOut,
-		PlayerLastWin:   state.PlayerLastWin,
-		PlayerTurn:      state.PlayerTurn,
-		Round:           state.Round,
-		Cups:            cups,
-		ExistingPlayers: state.ExistingPlayers,
-		Bets:            bets,
-		Balances:        balances,
+	var reveal *event.Reveal
+	if state.Reveal.Round > 0 {
+		r := toAppReveal(state.Reveal)
+		reveal = &r
+	}
+
+	var turnDeadline string
+	if !state.TurnDeadline.IsZero() {
+		turnDeadline = state.TurnDeadline.Format(time.RFC3339)
+	}
+
+	return event.State{
+		GameID:             state.GameID,
+		GameName:           state.GameName,
+		DateCreated:        state.DateCreated.Format(time.RFC3339),
+		AnteUSD:            state.AnteUSD,
+		Status:             state.Status,
+		Rules:              toAppRules(state.Rules),
+		Palifico:           state.Palifico,
+		Reveal:             reveal,
+		PlayerLastOut:      state.PlayerLastOut,
+		PlayerLastWin:      state.PlayerLastWin,
+		PlayerTurn:         state.PlayerTurn,
+		Round:              state.Round,
+		TurnDeadline:       turnDeadline,
+		Cups:               cups,
+		ExistingPlayers:    state.ExistingPlayers,
+		Bets:               bets,
+		Balances:           balances,
+		ServerSeedHash:     state.ServerSeedHash,
+		NextServerSeedHash: state.NextServerSeedHash,
 	}
 }
 
-type appBet struct {
-	Player common.Address `json:"account"`
-	Number int            `json:"number"`
-	Suit   int            `json:"suit"`
+func toAppRules(rules game.Rules) event.Rules {
+	var tournamentID string
+	if rules.Tournament() {
+		tournamentID = rules.TournamentID.String()
+	}
+
+	return event.Rules{
+		Ruleset:      rules.Ruleset,
+		MinPlayers:   rules.MinPlayers,
+		MaxPlayers:   rules.MaxPlayers,
+		TurnTimeout:  rules.TurnTimeout.String(),
+		Private:      rules.Private,
+		Elimination:  rules.Elimination,
+		MaxOuts:      rules.MaxOuts(),
+		WildOnes:     rules.WildOnes,
+		SpotOn:       rules.SpotOn,
+		Palifico:     rules.Palifico,
+		TournamentID: tournamentID,
+	}
+}
+
+type appTable struct {
+	GameID      uuid.UUID `json:"gameID"`
+	Status      string    `json:"status"`
+	AnteUSD     float64   `json:"anteUSD"`
+	Ruleset     string    `json:"ruleset"`
+	MinPlayers  int       `json:"minPlayers"`
+	MaxPlayers  int       `json:"maxPlayers"`
+	TurnTimeout string    `json:"turnTimeout"`
+	Players     int       `json:"players"`
+}
+
+func toAppTable(state game.State) appTable {
+	return appTable{
+		GameID:      state.GameID,
+		Status:      state.Status,
+		AnteUSD:     state.AnteUSD,
+		Ruleset:     state.Rules.Ruleset,
+		MinPlayers:  state.Rules.MinPlayers,
+		MaxPlayers:  state.Rules.MaxPlayers,
+		TurnTimeout: state.Rules.TurnTimeout.String(),
+		Players:     len(state.ExistingPlayers),
+	}
+}
+
+func toAppReveal(reveal game.Reveal) event.Reveal {
+	cups := make([]event.Cup, len(reveal.Cups))
+	for i, cup := range reveal.Cups {
+		cups[i] = toAppCup(cup, cup.Dice)
+	}
+
+	return event.Reveal{
+		Round:  reveal.Round,
+		Cups:   cups,
+		Bet:    toAppBet(reveal.Bet),
+		Total:  reveal.Total,
+		Exact:  reveal.Exact,
+		Winner: reveal.Winner,
+		Loser:  reveal.Loser,
+	}
 }
 
-func toAppBet(bet game.Bet) appBet {
-	return appBet{
+func toAppBet(bet game.Bet) event.Bet {
+	return event.Bet{
 		Player: bet.Player,
 		Number: bet.Number,
 		Suit:   bet.Suit,
 	}
 }
 
-type appCup struct {
-	Player  common.Address `json:"account"`
-	Dice    []int          `json:"dice"`
-	LastBet appBet         `json:"lastBet"`
-	Outs    int            `json:"outs"`
+type appNewBet struct {
+	Number int `json:"number"`
+	Suit   int `json:"suit"`
+}
+
+// toAppNewBet converts the number and suit of a bet from the url. Values
+// that aren't numbers are returned as field errors.
+func toAppNewBet(number string, suit string) (appNewBet, error) {
+	var nb appNewBet
+	var fields validate.FieldErrors
+
+	var err error
+	if nb.Number, err = strconv.Atoi(number); err != nil {
+		fields = append(fields, validate.FieldError{Field: "number", Err: fmt.Sprintf("number must be a whole number: number[%s]", number)})
+	}
+
+	if nb.Suit, err = strconv.Atoi(suit); err != nil {
+		fields = append(fields, validate.FieldError{Field: "suit", Err: fmt.Sprintf("suit must be a whole number: suit[%s]", suit)})
+	}
+
+	if len(fields) > 0 {
+		return appNewBet{}, fields
+	}
+
+	return nb, nil
+}
+
+// appNewChat is a chat message sent by a player over the websocket.
+type appNewChat struct {
+	Type    string `json:"type"`
+	GameID  string `json:"gameID" validate:"required,uuid"`
+	Message string `json:"message" validate:"required,max=200"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app appNewChat) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type appChat struct {
+	ID          uuid.UUID      `json:"id"`
+	GameID      uuid.UUID      `json:"gameID"`
+	Player      common.Address `json:"account"`
+	Message     string         `json:"message"`
+	DateCreated string         `json:"dateCreated"`
+}
+
+func toAppChat(msg chat.Message) appChat {
+	return appChat{
+		ID:          msg.ID,
+		GameID:      msg.GameID,
+		Player:      msg.Player,
+		Message:     msg.Text,
+		DateCreated: msg.DateCreated.Format(time.RFC3339),
+	}
+}
+
+// toAppChatError converts the problem with a chat message so it can be sent
+// back to the player. Unexpected errors are not shown to the player.
+func toAppChatError(err error) errs.Response {
+	if !errs.IsTrusted(err) {
+		return errs.Response{
+			Error: "unable to send message",
+		}
+	}
+
+	trsErr := errs.GetTrusted(err)
+
+	if validate.IsFieldErrors(trsErr.Err) {
+		return errs.Response{
+			Error:  "data validation error",
+			Fields: validate.GetFieldErrors(trsErr.Err).Fields(),
+		}
+	}
+
+	return errs.Response{
+		Error: trsErr.Error(),
+	}
+}
+
+func toAppCup(cup game.Cup, dice []int) event.Cup {
+	return event.Cup{
+		Player:     cup.Player,
+		Dice:       dice,
+		Outs:       cup.Outs,
+		ClientSeed: cup.ClientSeed,
+		Commitment: cup.Commitment,
+	}
+}
+
+type appProof struct {
+	GameID         uuid.UUID     `json:"gameID"`
+	Round          int           `json:"round"`
+	ServerSeed     string        `json:"serverSeed"`
+	ServerSeedHash string        `json:"serverSeedHash"`
+	Cups           []appProofCup `json:"cups"`
+}
+
+func toAppProof(gameID uuid.UUID, proof game.Proof) appProof {
+	cups := make([]appProofCup, len(proof.Cups))
+	for i, cup := range proof.Cups {
+		cups[i] = appProofCup{
+			Player:     cup.Player,
+			ClientSeed: cup.ClientSeed,
+			Dice:       cup.Dice,
+			Commitment: cup.Commitment,
+		}
+	}
+
+	return appProof{
+		GameID:         gameID,
+		Round:          proof.Round,
+		ServerSeed:     proof.ServerSeed,
+		ServerSeedHash: proof.ServerSeedHash,
+		Cups:           cups,
+	}
+}
+
+type appProofCup struct {
+	Player     common.Address `json:"account"`
+	ClientSeed string         `json:"clientSeed"`
+	Dice       []int          `json:"dice"`
+	Commitment string         `json:"commitment"`
 }
 
-func toAppCup(cup game.Cup, dice []int) appCup {
-	return appCup{
-		Player: cup.Player,
-		Dice:   dice,
-		Outs:   cup.Outs,
+type appOdds struct {
+	GameID     uuid.UUID      `json:"gameID"`
+	Round      int            `json:"round"`
+	Player     common.Address `json:"account"`
+	Dice       []int          `json:"dice"`
+	DiceInPlay int            `json:"diceInPlay"`
+	Wilds      bool           `json:"wilds"`
+	Bet        *appBetOdds    `json:"bet,omitempty"`
+	Suits      []appBetOdds   `json:"suits"`
+}
+
+type appBetOdds struct {
+	Number   int     `json:"number"`
+	Suit     int     `json:"suit"`
+	Known    int     `json:"known"`
+	Unknown  int     `json:"unknown"`
+	Expected float64 `json:"expected"`
+	AtLeast  float64 `json:"atLeast"`
+	Exactly  float64 `json:"exactly"`
+}
+
+// toAppOdds returns the odds for the player's cup. The odds of the specified
+// bet are included when the number is set, and the odds of every suit are
+// calculated for the number of dice the player can expect.
+func toAppOdds(state game.State, player common.Address, number int, suit int) appOdds {
+	dice := state.Cups[player].Dice
+
+	suits := make([]appBetOdds, game.NumberOfSuits)
+	for i := range suits {
+		o := state.Odds(player, 0, i+1)
+		suits[i] = toAppBetOdds(state.Odds(player, int(o.Expected), i+1))
+	}
+
+	var bet *appBetOdds
+	if number > 0 {
+		o := toAppBetOdds(state.Odds(player, number, suit))
+		bet = &o
+	}
+
+	return appOdds{
+		GameID:     state.GameID,
+		Round:      state.Round,
+		Player:     player,
+		Dice:       dice,
+		DiceInPlay: state.DiceInPlay(),
+		Wilds:      state.Wilds(),
+		Bet:        bet,
+		Suits:      suits,
+	}
+}
+
+func toAppBetOdds(o odds.Odds) appBetOdds {
+	return appBetOdds{
+		Number:   o.Number,
+		Suit:     o.Suit,
+		Known:    o.Known,
+		Unknown:  o.Unknown,
+		Expected: o.Expected,
+		AtLeast:  o.AtLeast,
+		Exactly:  o.Exactly,
+	}
+}
+
+type appRematch struct {
+	GameID    uuid.UUID        `json:"gameID"`
+	NewGameID string           `json:"newGameID,omitempty"`
+	Accepted  []common.Address `json:"accepted"`
+	Declined  []common.Address `json:"declined"`
+	Pending   []common.Address `json:"pending"`
+}
+
+func toAppRematch(rm game.Rematch) appRematch {
+	var newGameID string
+	if rm.NewGameID != uuid.Nil {
+		newGameID = rm.NewGameID.String()
+	}
+
+	return appRematch{
+		GameID:    rm.GameID,
+		NewGameID: newGameID,
+		Accepted:  rm.Accepted,
+		Declined:  rm.Declined,
+		Pending:   rm.Pending,
+	}
+}
+
+type appSummary struct {
+	GameID      uuid.UUID        `json:"gameID"`
+	DateCreated string           `json:"dateCreated"`
+	AnteUSD     float64          `json:"anteUSD"`
+	Ruleset     string           `json:"ruleset"`
+	Private     bool             `json:"private"`
+	Status      string           `json:"status"`
+	Round       int              `json:"round"`
+	Players     []common.Address `json:"players"`
+	Winner      string           `json:"winner,omitempty"`
+}
+
+func toAppSummary(s game.Summary) appSummary {
+	var empty common.Address
+
+	var winner string
+	if s.Winner != empty {
+		winner = s.Winner.Hex()
+	}
+
+	return appSummary{
+		GameID:      s.GameID,
+		DateCreated: s.DateCreated.Format(time.RFC3339),
+		AnteUSD:     s.AnteUSD,
+		Ruleset:     s.Ruleset,
+		Private:     s.Private,
+		Status:      s.Status,
+		Round:       s.Round,
+		Players:     s.Players,
+		Winner:      winner,
+	}
+}
+
+type appRound struct {
+	Round          int              `json:"round"`
+	Status         string           `json:"status"`
+	PlayerLastOut  common.Address   `json:"lastOut"`
+	PlayerLastWin  common.Address   `json:"lastWin"`
+	PlayerTurn     common.Address   `json:"currentID"`
+	Players        []common.Address `json:"playerOrder"`
+	Cups           []event.Cup      `json:"cups"`
+	Bets           []event.Bet      `json:"bets"`
+	Balances       []string         `json:"balances"`
+	ServerSeed     string           `json:"serverSeed,omitempty"`
+	ServerSeedHash string           `json:"serverSeedHash"`
+}
+
+// toAppRound converts a stored round. The rounds are stored once the dice
+// have been revealed, so the dice of every player are shown.
+func toAppRound(state game.State) appRound {
+	var cups []event.Cup
+	for _, player := range state.ExistingPlayers {
+		if cup, exists := state.Cups[player]; exists {
+			cups = append(cups, toAppCup(cup, cup.Dice))
+		}
+	}
+
+	var bets []event.Bet
+	for _, bet := range state.Bets {
+		bets = append(bets, toAppBet(bet))
+	}
+
+	var balances []string
+	for _, balance := range state.Balances {
+		balances = append(balances, balance.Amount)
+	}
+
+	return appRound{
+		Round:          state.Round,
+		Status:         state.Status,
+		PlayerLastOut:  state.PlayerLastOut,
+		PlayerLastWin:  state.PlayerLastWin,
+		PlayerTurn:     state.PlayerTurn,
+		Players:        state.ExistingPlayers,
+		Cups:           cups,
+		Bets:           bets,
+		Balances:       balances,
+		ServerSeed:     state.ServerSeed,
+		ServerSeedHash: state.ServerSeedHash,
+	}
+}
+
+// appReplay is the replay document. The version is changed when the
+// document changes in a way that breaks existing viewers.
+type appReplay struct {
+	Version     int              `json:"version"`
+	GameID      uuid.UUID        `json:"gameID"`
+	DateCreated string           `json:"dateCreated"`
+	AnteUSD     float64          `json:"anteUSD"`
+	Rules       event.Rules      `json:"rules"`
+	Status      string           `json:"status"`
+	Players     []common.Address `json:"players"`
+	Winner      common.Address   `json:"winner"`
+	Rounds      []appReplayRound `json:"rounds"`
+}
+
+func toAppReplay(rp game.Replay) appReplay {
+	rounds := make([]appReplayRound, len(rp.Rounds))
+	for i, round := range rp.Rounds {
+		rounds[i] = toAppReplayRound(round)
+	}
+
+	return appReplay{
+		Version:     rp.Version,
+		GameID:      rp.GameID,
+		DateCreated: rp.DateCreated.Format(time.RFC3339),
+		AnteUSD:     rp.AnteUSD,
+		Rules:       toAppRules(rp.Rules),
+		Status:      rp.Status,
+		Players:     rp.Players,
+		Winner:      rp.Winner,
+		Rounds:      rounds,
+	}
+}
+
+type appReplayRound struct {
+	Round          int            `json:"round"`
+	Palifico       bool           `json:"palifico"`
+	Cups           []event.Cup    `json:"cups"`
+	Bets           []event.Bet    `json:"bets"`
+	Result         string         `json:"result"`
+	Total          int            `json:"total"`
+	Winner         common.Address `json:"winner"`
+	Loser          common.Address `json:"loser"`
+	ServerSeed     string         `json:"serverSeed"`
+	ServerSeedHash string         `json:"serverSeedHash"`
+}
+
+func toAppReplayRound(round game.ReplayRound) appReplayRound {
+	cups := make([]event.Cup, len(round.Cups))
+	for i, cup := range round.Cups {
+		cups[i] = toAppCup(cup, cup.Dice)
+	}
+
+	bets := make([]event.Bet, len(round.Bets))
+	for i, bet := range round.Bets {
+		bets[i] = toAppBet(bet)
+	}
+
+	return appReplayRound{
+		Round:          round.Round,
+		Palifico:       round.Palifico,
+		Cups:           cups,
+		Bets:           bets,
+		Result:         round.Result,
+		Total:          round.Total,
+		Winner:         round.Winner,
+		Loser:          round.Loser,
+		ServerSeed:     round.ServerSeed,
+		ServerSeedHash: round.ServerSeedHash,
 	}
 }
@@ -6,6 +6,10 @@ import (
 
 	"github.com/ardanlabs/ethereum/currency"
 	"github.com/ardanlabs/liarsdice/business/core/bank"
+	"github.com/ardanlabs/liarsdice/business/core/bot"
+	"github.com/ardanlabs/liarsdice/business/core/chat"
+	"github.com/ardanlabs/liarsdice/business/core/chat/stores/chatdb"
+	"github.com/ardanlabs/liarsdice/business/core/game"
 	"github.com/ardanlabs/liarsdice/business/core/game/stores/gamedb"
 	"github.com/ardanlabs/liarsdice/business/web/auth"
 	"github.com/ardanlabs/liarsdice/business/web/mid"
@@ -23,10 +27,14 @@ type Config struct {
 	Bank           *bank.Bank
 	DB             *sqlx.DB
 	Evts           *events
+	Bots           *bot.Bots
 	AnteUSD        float64
+	TurnTimeout    time.Duration
+	TimeoutAction  string
 	ActiveKID      string
 	BankTimeout    time.Duration
 	ConnectTimeout time.Duration
+	InviteKey      []byte
 }
 
 // Routes adds specific routes for this group.
@@ -36,14 +44,21 @@ func Routes(app *web.App, cfg Config) {
 	hdl := handlers{
 		converter:      cfg.Converter,
 		bank:           cfg.Bank,
+		banker:         cfg.Bots.Banker(cfg.Bank),
+		bots:           cfg.Bots,
 		storer:         gamedb.NewStore(cfg.Log, cfg.DB),
+		chat:           chat.NewCore(cfg.Log, chatdb.NewStore(cfg.Log, cfg.DB)),
+		dicer:          game.NewCryptoDicer(),
 		log:            cfg.Log,
 		ws:             websocket.Upgrader{},
 		auth:           cfg.Auth,
 		activeKID:      cfg.ActiveKID,
 		anteUSD:        cfg.AnteUSD,
+		turnTimeout:    cfg.TurnTimeout,
+		timeoutAction:  cfg.TimeoutAction,
 		bankTimeout:    cfg.BankTimeout,
 		connectTimeout: cfg.ConnectTimeout,
+		inviteKey:      cfg.InviteKey,
 	}
 
 	app.Handle(http.MethodPost, version, "/game/connect", hdl.connect)
@@ -55,15 +70,26 @@ func Routes(app *web.App, cfg Config) {
 	app.Handle(http.MethodGet, version, "/game/balance", hdl.balance, mid.Authenticate(cfg.Auth))
 	app.Handle(http.MethodGet, version, "/game/tables", hdl.tables, mid.Authenticate(cfg.Auth))
 
+	app.Handle(http.MethodGet, version, "/games", hdl.history, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/games/:id/rounds", hdl.rounds, mid.Authenticate(cfg.Auth))
+
+	app.Handle(http.MethodGet, version, "/game/:id/replay", hdl.replay, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/game/:id/chat", hdl.chatHistory, mid.Authenticate(cfg.Auth))
 	app.Handle(http.MethodGet, version, "/game/:id/state", hdl.state, mid.Authenticate(cfg.Auth))
 	app.Handle(http.MethodGet, version, "/game/:id/join", hdl.join, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodPost, version, "/game/:id/leave", hdl.leave, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/game/:id/watch", hdl.watch, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/game/:id/bots/:number", hdl.addBots, mid.Authenticate(cfg.Auth))
 	app.Handle(http.MethodGet, version, "/game/:id/start", hdl.startGame, mid.Authenticate(cfg.Auth))
 	app.Handle(http.MethodGet, version, "/game/:id/rolldice", hdl.rollDice, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/game/:id/seed/:seed", hdl.clientSeed, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/game/:id/proof/:round", hdl.proof, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/game/:id/odds", hdl.odds, mid.Authenticate(cfg.Auth))
 	app.Handle(http.MethodGet, version, "/game/:id/bet/:number/:suit", hdl.bet, mid.Authenticate(cfg.Auth))
 	app.Handle(http.MethodGet, version, "/game/:id/liar", hdl.callLiar, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/game/:id/exact", hdl.callExact, mid.Authenticate(cfg.Auth))
 	app.Handle(http.MethodGet, version, "/game/:id/reconcile", hdl.reconcile, mid.Authenticate(cfg.Auth))
-
-	// Timeout Situations with a player
-	app.Handle(http.MethodGet, version, "/game/:id/next", hdl.nextTurn, mid.Authenticate(cfg.Auth))
-	app.Handle(http.MethodGet, version, "/game/:id/out/:outs", hdl.updateOut, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/game/:id/rematch", hdl.rematch, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodPost, version, "/game/:id/rematch/accept", hdl.answerRematch, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodPost, version, "/game/:id/rematch/decline", hdl.answerRematch, mid.Authenticate(cfg.Auth))
 }
@@ -0,0 +1,34 @@
+package playergrp
+
+import (
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type appStats struct {
+	Player     common.Address `json:"player"`
+	Games      int            `json:"games"`
+	Wins       int            `json:"wins"`
+	WinRate    float64        `json:"winRate"`
+	Calls      int            `json:"calls"`
+	CallsRight int            `json:"callsRight"`
+	CallRate   float64        `json:"callRate"`
+	Bets       int            `json:"bets"`
+	Bluffs     int            `json:"bluffs"`
+	BluffRate  float64        `json:"bluffRate"`
+}
+
+func toAppStats(stats game.Stats) appStats {
+	return appStats{
+		Player:     stats.Player,
+		Games:      stats.Games,
+		Wins:       stats.Wins,
+		WinRate:    stats.WinRate(),
+		Calls:      stats.Calls,
+		CallsRight: stats.CallsRight,
+		CallRate:   stats.CallRate(),
+		Bets:       stats.Bets,
+		Bluffs:     stats.Bluffs,
+		BluffRate:  stats.BluffRate(),
+	}
+}
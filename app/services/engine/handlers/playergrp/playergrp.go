@@ -0,0 +1,36 @@
+// Package playergrp provides the handlers for player information.
+package playergrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ardanlabs/liarsdice/business/core/game/stores/gamedb"
+	"github.com/ardanlabs/liarsdice/business/web/errs"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ardanlabs/liarsdice/foundation/web"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type handlers struct {
+	log    *logger.Logger
+	storer *gamedb.Store
+}
+
+// stats returns the statistics of the specified player over the games that
+// have been stored.
+func (h *handlers) stats(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	address := web.Param(r, "address")
+	if !common.IsHexAddress(address) {
+		return errs.NewTrusted(errors.New("invalid account address"), http.StatusBadRequest)
+	}
+
+	stats, err := h.storer.QueryStats(ctx, common.HexToAddress(address))
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	return web.Respond(ctx, w, toAppStats(stats), http.StatusOK)
+}
@@ -0,0 +1,31 @@
+package playergrp
+
+import (
+	"net/http"
+
+	"github.com/ardanlabs/liarsdice/business/core/game/stores/gamedb"
+	"github.com/ardanlabs/liarsdice/business/web/auth"
+	"github.com/ardanlabs/liarsdice/business/web/mid"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ardanlabs/liarsdice/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log  *logger.Logger
+	Auth *auth.Auth
+	DB   *sqlx.DB
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	hdl := handlers{
+		log:    cfg.Log,
+		storer: gamedb.NewStore(cfg.Log, cfg.DB),
+	}
+
+	app.Handle(http.MethodGet, version, "/players/:address/stats", hdl.stats, mid.Authenticate(cfg.Auth))
+}
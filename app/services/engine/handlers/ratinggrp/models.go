@@ -0,0 +1,36 @@
+package ratinggrp
+
+import (
+	"math"
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/core/rating"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type appRating struct {
+	Rank        int            `json:"rank,omitempty"`
+	Player      common.Address `json:"player"`
+	Rating      int            `json:"rating"`
+	Games       int            `json:"games"`
+	Wins        int            `json:"wins"`
+	DateUpdated string         `json:"dateUpdated,omitempty"`
+}
+
+func toAppRating(r rating.Rating, rank int) appRating {
+
+	// A player who hasn't played a rated game was never updated.
+	var dateUpdated string
+	if !r.DateUpdated.IsZero() {
+		dateUpdated = r.DateUpdated.Format(time.RFC3339)
+	}
+
+	return appRating{
+		Rank:        rank,
+		Player:      r.Player,
+		Rating:      int(math.Round(r.Rating)),
+		Games:       r.Games,
+		Wins:        r.Wins,
+		DateUpdated: dateUpdated,
+	}
+}
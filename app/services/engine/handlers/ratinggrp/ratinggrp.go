@@ -0,0 +1,82 @@
+// Package ratinggrp provides the handlers for player ratings.
+package ratinggrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ardanlabs/liarsdice/business/core/rating"
+	"github.com/ardanlabs/liarsdice/business/web/errs"
+	"github.com/ardanlabs/liarsdice/business/web/page"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ardanlabs/liarsdice/foundation/web"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Represents the paging limits of the leaderboard.
+const (
+	defaultRowsPerPage = 20
+	maxRowsPerPage     = 100
+)
+
+type handlers struct {
+	log     *logger.Logger
+	ratings *rating.Core
+}
+
+// leaderboard returns a page of the players from the highest rating to the
+// lowest.
+func (h *handlers) leaderboard(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+
+	pageNumber := 1
+	if v := query.Get("page"); v != "" {
+		var err error
+		if pageNumber, err = strconv.Atoi(v); err != nil || pageNumber < 1 {
+			return errs.NewTrusted(fmt.Errorf("invalid page number %q", v), http.StatusBadRequest)
+		}
+	}
+
+	rowsPerPage := defaultRowsPerPage
+	if v := query.Get("rows"); v != "" {
+		var err error
+		if rowsPerPage, err = strconv.Atoi(v); err != nil || rowsPerPage < 1 || rowsPerPage > maxRowsPerPage {
+			return errs.NewTrusted(fmt.Errorf("invalid rows per page %q, must be between 1 and %d", v, maxRowsPerPage), http.StatusBadRequest)
+		}
+	}
+
+	ratings, err := h.ratings.Query(ctx, pageNumber, rowsPerPage)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	total, err := h.ratings.Count(ctx)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+
+	items := make([]appRating, len(ratings))
+	for i, rtg := range ratings {
+		items[i] = toAppRating(rtg, (pageNumber-1)*rowsPerPage+i+1)
+	}
+
+	return web.Respond(ctx, w, page.NewDocument(items, total, pageNumber, rowsPerPage), http.StatusOK)
+}
+
+// rating returns the rating of the specified player.
+func (h *handlers) rating(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	address := web.Param(r, "address")
+	if !common.IsHexAddress(address) {
+		return errs.NewTrusted(errors.New("invalid account address"), http.StatusBadRequest)
+	}
+
+	rtg, err := h.ratings.QueryByPlayer(ctx, common.HexToAddress(address))
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	return web.Respond(ctx, w, toAppRating(rtg, 0), http.StatusOK)
+}
@@ -0,0 +1,31 @@
+package ratinggrp
+
+import (
+	"net/http"
+
+	"github.com/ardanlabs/liarsdice/business/core/rating"
+	"github.com/ardanlabs/liarsdice/business/web/auth"
+	"github.com/ardanlabs/liarsdice/business/web/mid"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ardanlabs/liarsdice/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log     *logger.Logger
+	Auth    *auth.Auth
+	Ratings *rating.Core
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	hdl := handlers{
+		log:     cfg.Log,
+		ratings: cfg.Ratings,
+	}
+
+	app.Handle(http.MethodGet, version, "/leaderboard", hdl.leaderboard, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/players/:address/rating", hdl.rating, mid.Authenticate(cfg.Auth))
+}
@@ -0,0 +1,71 @@
+package tournamentgrp
+
+import (
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/core/tournament"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+type appTournament struct {
+	ID          uuid.UUID        `json:"tournamentID"`
+	Creator     common.Address   `json:"creator"`
+	BuyInUSD    float64          `json:"buyInUSD"`
+	TableSize   int              `json:"tableSize"`
+	PrizeSplit  []int            `json:"prizeSplit"`
+	Status      string           `json:"status"`
+	Stage       int              `json:"stage"`
+	Players     []common.Address `json:"players"`
+	Tables      []appTable       `json:"tables"`
+	Places      []common.Address `json:"places"`
+	DateCreated string           `json:"dateCreated"`
+}
+
+func toAppTournament(t tournament.Tournament) appTournament {
+	tables := make([]appTable, len(t.Tables))
+	for i, tb := range t.Tables {
+		tables[i] = toAppTable(tb)
+	}
+
+	return appTournament{
+		ID:          t.ID,
+		Creator:     t.Creator,
+		BuyInUSD:    t.BuyInUSD,
+		TableSize:   t.TableSize,
+		PrizeSplit:  t.PrizeSplit,
+		Status:      t.Status,
+		Stage:       t.Stage,
+		Players:     t.Players,
+		Tables:      tables,
+		Places:      t.Places,
+		DateCreated: t.DateCreated.Format(time.RFC3339),
+	}
+}
+
+type appTable struct {
+	Stage     int              `json:"stage"`
+	Number    int              `json:"number"`
+	GameID    string           `json:"gameID,omitempty"`
+	Players   []common.Address `json:"players"`
+	Standings []common.Address `json:"standings"`
+	Finished  bool             `json:"finished"`
+}
+
+func toAppTable(tb tournament.Table) appTable {
+
+	// A table with a bye doesn't have a game.
+	var gameID string
+	if tb.GameID != uuid.Nil {
+		gameID = tb.GameID.String()
+	}
+
+	return appTable{
+		Stage:     tb.Stage,
+		Number:    tb.Number,
+		GameID:    gameID,
+		Players:   tb.Players,
+		Standings: tb.Standings,
+		Finished:  tb.Finished,
+	}
+}
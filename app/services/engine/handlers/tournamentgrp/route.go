@@ -0,0 +1,38 @@
+package tournamentgrp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/core/tournament"
+	"github.com/ardanlabs/liarsdice/business/web/auth"
+	"github.com/ardanlabs/liarsdice/business/web/mid"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ardanlabs/liarsdice/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log         *logger.Logger
+	Auth        *auth.Auth
+	Tournaments *tournament.Manager
+	BankTimeout time.Duration
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	hdl := handlers{
+		log:         cfg.Log,
+		tournaments: cfg.Tournaments,
+		bankTimeout: cfg.BankTimeout,
+	}
+
+	app.Handle(http.MethodGet, version, "/tournament/new", hdl.newTournament, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/tournament/list", hdl.list, mid.Authenticate(cfg.Auth))
+
+	app.Handle(http.MethodGet, version, "/tournament/:id/state", hdl.state, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/tournament/:id/register", hdl.register, mid.Authenticate(cfg.Auth))
+	app.Handle(http.MethodGet, version, "/tournament/:id/start", hdl.start, mid.Authenticate(cfg.Auth))
+}
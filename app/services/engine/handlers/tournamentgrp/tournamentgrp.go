@@ -0,0 +1,145 @@
+// Package tournamentgrp provides the handlers for tournament play.
+package tournamentgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/core/tournament"
+	"github.com/ardanlabs/liarsdice/business/web/errs"
+	"github.com/ardanlabs/liarsdice/business/web/mid"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ardanlabs/liarsdice/foundation/web"
+	"github.com/google/uuid"
+)
+
+type handlers struct {
+	log         *logger.Logger
+	tournaments *tournament.Manager
+	bankTimeout time.Duration
+}
+
+// newTournament creates a new tournament and registers the creator.
+func (h *handlers) newTournament(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+
+	buyInUSD, err := strconv.ParseFloat(query.Get("buyin"), 64)
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("converting buy-in: %s", err), http.StatusBadRequest)
+	}
+
+	tableSize, err := strconv.Atoi(query.Get("size"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("converting table size: %s", err), http.StatusBadRequest)
+	}
+
+	var prizeSplit []int
+	if v := query.Get("split"); v != "" {
+		for _, pct := range strings.Split(v, ",") {
+			n, err := strconv.Atoi(pct)
+			if err != nil {
+				return errs.NewTrusted(fmt.Errorf("converting prize split: %s", err), http.StatusBadRequest)
+			}
+			prizeSplit = append(prizeSplit, n)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.bankTimeout)
+	defer cancel()
+
+	t, err := h.tournaments.Create(ctx, mid.GetSubject(ctx), buyInUSD, tableSize, prizeSplit)
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to create tournament: %w", err), http.StatusBadRequest)
+	}
+
+	return web.Respond(ctx, w, toAppTournament(t), http.StatusOK)
+}
+
+// list returns the tournaments that are open or being played.
+func (h *handlers) list(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	active := h.tournaments.Active()
+
+	tournaments := make([]appTournament, len(active))
+	for i, t := range active {
+		tournaments[i] = toAppTournament(t)
+	}
+
+	info := struct {
+		Tournaments []appTournament `json:"tournaments"`
+	}{
+		Tournaments: tournaments,
+	}
+
+	return web.Respond(ctx, w, info, http.StatusOK)
+}
+
+// state returns the tables and standings of the tournament.
+func (h *handlers) state(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	tournamentID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse tournament id: %w", err), http.StatusBadRequest)
+	}
+
+	t, err := h.tournaments.Retrieve(ctx, tournamentID)
+	if err != nil {
+		if errors.Is(err, tournament.ErrNotFound) {
+			return errs.NewTrusted(errors.New("no tournament exists"), http.StatusNotFound)
+		}
+		return fmt.Errorf("retrieve: tournamentID[%s]: %w", tournamentID, err)
+	}
+
+	return web.Respond(ctx, w, toAppTournament(t), http.StatusOK)
+}
+
+// register adds the player to a tournament that hasn't started.
+func (h *handlers) register(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	tournamentID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse tournament id: %w", err), http.StatusBadRequest)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.bankTimeout)
+	defer cancel()
+
+	t, err := h.tournaments.Register(ctx, tournamentID, mid.GetSubject(ctx))
+	if err != nil {
+		if errors.Is(err, tournament.ErrNotFound) {
+			return errs.NewTrusted(errors.New("no tournament exists"), http.StatusNotFound)
+		}
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	return web.Respond(ctx, w, toAppTournament(t), http.StatusOK)
+}
+
+// start seats the registered players and starts the games of the first
+// stage. Only the creator of the tournament can start it.
+func (h *handlers) start(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	tournamentID, err := uuid.Parse(web.Param(r, "id"))
+	if err != nil {
+		return errs.NewTrusted(fmt.Errorf("unable to parse tournament id: %w", err), http.StatusBadRequest)
+	}
+
+	t, err := h.tournaments.Retrieve(ctx, tournamentID)
+	if err != nil {
+		if errors.Is(err, tournament.ErrNotFound) {
+			return errs.NewTrusted(errors.New("no tournament exists"), http.StatusNotFound)
+		}
+		return fmt.Errorf("retrieve: tournamentID[%s]: %w", tournamentID, err)
+	}
+
+	if t.Creator != mid.GetSubject(ctx) {
+		return errs.NewTrusted(errors.New("only the creator can start the tournament"), http.StatusForbidden)
+	}
+
+	if t, err = h.tournaments.Begin(ctx, tournamentID); err != nil {
+		return errs.NewTrusted(err, http.StatusBadRequest)
+	}
+
+	return web.Respond(ctx, w, toAppTournament(t), http.StatusOK)
+}
@@ -17,11 +17,20 @@ import (
 	"github.com/ardanlabs/ethereum"
 	"github.com/ardanlabs/ethereum/currency"
 	"github.com/ardanlabs/liarsdice/app/services/engine/build/all"
+	"github.com/ardanlabs/liarsdice/app/services/engine/handlers/gamegrp"
 	scbank "github.com/ardanlabs/liarsdice/business/contract/go/bank"
 	"github.com/ardanlabs/liarsdice/business/core/bank"
+	"github.com/ardanlabs/liarsdice/business/core/bot"
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ardanlabs/liarsdice/business/core/game/stores/gamedb"
+	"github.com/ardanlabs/liarsdice/business/core/rating"
+	"github.com/ardanlabs/liarsdice/business/core/rating/stores/ratingdb"
+	"github.com/ardanlabs/liarsdice/business/core/tournament"
+	"github.com/ardanlabs/liarsdice/business/core/tournament/stores/tournamentdb"
 	"github.com/ardanlabs/liarsdice/business/data/sqldb"
 	"github.com/ardanlabs/liarsdice/business/web/auth"
 	"github.com/ardanlabs/liarsdice/business/web/debug"
+	"github.com/ardanlabs/liarsdice/business/web/metrics"
 	"github.com/ardanlabs/liarsdice/business/web/mux"
 	"github.com/ardanlabs/liarsdice/foundation/keystore"
 	"github.com/ardanlabs/liarsdice/foundation/logger"
@@ -32,9 +41,6 @@ import (
 
 /*
 	-- Game Engine
-	Now that a player can be in multiple games, we need to better control their available balance.
-	Once Liar is called, the status needs to share the dice for all players.
-	Add in-game chat support.
 	Add a Drain function to the smart contract.
 	Add an account fix function to adjust balances.
 	Have engine sign all transactions to the smart contract.
@@ -114,6 +120,15 @@ func run(ctx context.Context, log *logger.Logger) error {
 			ContractID     string        `conf:"default:0x0"`
 			AnteUSD        float64       `conf:"default:5"`
 			ConnectTimeout time.Duration `conf:"default:60s"`
+			TurnTimeout    time.Duration `conf:"default:60s"`
+			TimeoutAction  string        `conf:"default:bid"`
+			TurnCheck      time.Duration `conf:"default:1s"`
+			BotInterval    time.Duration `conf:"default:2s"`
+			ReapInterval   time.Duration `conf:"default:1m"`
+			ReconcileGrace time.Duration `conf:"default:10m"`
+			IdleTTL        time.Duration `conf:"default:1h"`
+			AutoReconcile  bool          `conf:"default:false"`
+			TourneyCheck   time.Duration `conf:"default:2s"`
 		}
 		Bank struct {
 			KeysFolder       string        `conf:"default:zarf/ethereum/keystore/"`
@@ -246,6 +261,121 @@ func run(ctx context.Context, log *logger.Logger) error {
 		return fmt.Errorf("connecting to bankClient: %w", err)
 	}
 
+	// -------------------------------------------------------------------------
+	// Start Turn Scheduler
+
+	log.Info(ctx, "startup", "status", "initializing turn scheduler", "turnTimeout", cfg.Game.TurnTimeout, "timeoutAction", cfg.Game.TimeoutAction)
+
+	scheduler := game.NewScheduler(log, cfg.Game.TurnCheck, cfg.Bank.Timeout)
+	defer func() {
+		log.Info(ctx, "shutdown", "status", "stopping turn scheduler")
+		scheduler.Shutdown()
+	}()
+
+	// -------------------------------------------------------------------------
+	// Start Bots
+
+	log.Info(ctx, "startup", "status", "initializing bots", "interval", cfg.Game.BotInterval)
+
+	bots := bot.New(log, bankClient.Client().Address(), game.NewCryptoDicer(), cfg.Game.BotInterval, cfg.Bank.Timeout)
+	defer func() {
+		log.Info(ctx, "shutdown", "status", "stopping bots")
+		bots.Shutdown()
+	}()
+
+	// -------------------------------------------------------------------------
+	// Start Table Reaper
+
+	log.Info(ctx, "startup", "status", "initializing table reaper", "reconcileGrace", cfg.Game.ReconcileGrace, "idleTTL", cfg.Game.IdleTTL, "autoReconcile", cfg.Game.AutoReconcile)
+
+	reaper := game.NewReaper(log, cfg.Game.ReapInterval, cfg.Game.ReconcileGrace, cfg.Game.IdleTTL, cfg.Bank.Timeout, cfg.Game.AutoReconcile)
+	reaper.Start(func(ctx context.Context, e game.Eviction) {
+		ctx = metrics.Set(ctx)
+
+		switch e.Reason {
+		case game.EvictReconciled:
+			metrics.AddReconciledEvictions(ctx)
+		case game.EvictAbandoned:
+			metrics.AddAbandonedEvictions(ctx)
+		}
+	})
+	defer func() {
+		log.Info(ctx, "shutdown", "status", "stopping table reaper")
+		reaper.Shutdown()
+	}()
+
+	// -------------------------------------------------------------------------
+	// Initialize Player Ratings
+
+	log.Info(ctx, "startup", "status", "initializing player ratings")
+
+	// The players of every game are rated once the game is reconciled.
+	ratings := rating.NewCore(log, ratingdb.NewStore(log, db))
+	game.Tables.SetRater(ratings)
+
+	// -------------------------------------------------------------------------
+	// Resume Games
+
+	log.Info(ctx, "startup", "status", "rehydrating games")
+
+	// The games that were in play before the engine was restarted are loaded
+	// before the scheduler and bots start acting on the tables.
+	rehydrate := func() (int, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Bank.Timeout)
+		defer cancel()
+
+		return game.Tables.Rehydrate(ctx, log, converter, gamedb.NewStore(log, db), bots.Banker(bankClient), game.NewCryptoDicer())
+	}
+
+	loaded, err := rehydrate()
+	if err != nil {
+		log.Error(ctx, "startup", "status", "rehydrating games", "ERROR", err)
+	}
+	log.Info(ctx, "startup", "status", "games rehydrated", "games", loaded)
+
+	// The scheduler and bots send the game events to the players.
+	gamegrp.SetLogger(log)
+
+	scheduler.Start(gamegrp.Timeout)
+	bots.Start(gamegrp.BotAction)
+
+	// -------------------------------------------------------------------------
+	// Start Tournaments
+
+	log.Info(ctx, "startup", "status", "initializing tournaments", "interval", cfg.Game.TourneyCheck)
+
+	// The tournament tables are played with the classic rules and the turn
+	// timeout of the engine.
+	tourneyRules, err := game.ParseRules(game.RulesetClassic)
+	if err != nil {
+		return fmt.Errorf("tournament rules: %w", err)
+	}
+	tourneyRules.TurnTimeout = cfg.Game.TurnTimeout
+	tourneyRules.TimeoutAction = cfg.Game.TimeoutAction
+
+	tournaments := tournament.New(log, converter, tournamentdb.NewStore(log, db), gamedb.NewStore(log, db), bots.Banker(bankClient), game.NewCryptoDicer(), tourneyRules, cfg.Game.TourneyCheck, cfg.Bank.Timeout)
+	defer func() {
+		log.Info(ctx, "shutdown", "status", "stopping tournaments")
+		tournaments.Shutdown()
+	}()
+
+	// The tournaments that were open or being played before the engine was
+	// restarted are loaded before the manager starts advancing them.
+	rehydrateTournaments := func() (int, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Bank.Timeout)
+		defer cancel()
+
+		return tournaments.Rehydrate(ctx)
+	}
+
+	loaded, err = rehydrateTournaments()
+	if err != nil {
+		log.Error(ctx, "startup", "status", "rehydrating tournaments", "ERROR", err)
+	}
+	log.Info(ctx, "startup", "status", "tournaments rehydrated", "tournaments", loaded)
+
+	tournaments.Start()
+
 	// -------------------------------------------------------------------------
 	// Start Debug Service
 
@@ -265,6 +395,10 @@ func run(ctx context.Context, log *logger.Logger) error {
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
 
+	// Invite codes for private tables are signed with a key derived from the
+	// engine's private key, so the codes are still valid after a restart.
+	inviteKey := crypto.Keccak256(crypto.FromECDSA(ecdsaKey), []byte("invite"))
+
 	cfgMux := mux.Config{
 		Shutdown:       shutdown,
 		Log:            log,
@@ -272,10 +406,16 @@ func run(ctx context.Context, log *logger.Logger) error {
 		Converter:      converter,
 		Bank:           bankClient,
 		DB:             db,
+		Bots:           bots,
+		Tournaments:    tournaments,
+		Ratings:        ratings,
 		AnteUSD:        cfg.Game.AnteUSD,
+		TurnTimeout:    cfg.Game.TurnTimeout,
+		TimeoutAction:  cfg.Game.TimeoutAction,
 		ActiveKID:      cfg.Auth.ActiveKID,
 		BankTimeout:    cfg.Bank.Timeout,
 		ConnectTimeout: cfg.Game.ConnectTimeout,
+		InviteKey:      inviteKey,
 	}
 
 	api := http.Server{
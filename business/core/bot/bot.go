@@ -0,0 +1,351 @@
+// Package bot provides players run by the engine that can fill the empty
+// seats at a table.
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+// Represents the actions a bot can take in a game.
+const (
+	ActionRoll      = "rolldice"
+	ActionBet       = "bet"
+	ActionLiar      = "callliar"
+	ActionExact     = "callexact"
+	ActionReconcile = "reconcile"
+)
+
+// Action represents a play a bot made in a game.
+type Action struct {
+	GameID uuid.UUID
+	Player common.Address
+	Type   string
+}
+
+// seat represents a bot sitting at a table.
+type seat struct {
+	player   common.Address
+	strategy Strategy
+}
+
+// Bots manages the bots sitting at the tables and plays their turns.
+type Bots struct {
+	log         *logger.Logger
+	engine      common.Address
+	dicer       game.Dicer
+	interval    time.Duration
+	bankTimeout time.Duration
+	mu          sync.RWMutex
+	seats       map[uuid.UUID][]seat
+	shutdown    chan struct{}
+	wg          sync.WaitGroup
+}
+
+// New constructs the bots for the engine with the specified address. The
+// bots check the tables for their turn on the specified interval.
+func New(log *logger.Logger, engine common.Address, dicer game.Dicer, interval time.Duration, bankTimeout time.Duration) *Bots {
+	return &Bots{
+		log:         log,
+		engine:      engine,
+		dicer:       dicer,
+		interval:    interval,
+		bankTimeout: bankTimeout,
+		seats:       make(map[uuid.UUID][]seat),
+		shutdown:    make(chan struct{}),
+	}
+}
+
+// Add seats the specified number of bots at the table. Every bot plays with
+// the named strategy. The addresses of the bots that were seated are
+// returned, even when an error stops the remaining bots from being seated.
+func (b *Bots) Add(ctx context.Context, g *game.Game, n int, strategy string) ([]common.Address, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("number of bots must be greater than zero: n[%d]", n)
+	}
+
+	s, err := ParseStrategy(strategy, b.dicer)
+	if err != nil {
+		return nil, err
+	}
+
+	var players []common.Address
+	for i := 0; i < n; i++ {
+		st := seat{
+			player:   b.address(g.ID(), len(b.table(g.ID()))),
+			strategy: s,
+		}
+
+		// The bot is registered first so the next bot gets the next seat.
+		b.sit(g.ID(), st)
+
+		if err := g.AddAccount(ctx, st.player); err != nil {
+			b.stand(g.ID(), st.player)
+			return players, err
+		}
+
+		b.log.Info(ctx, "bot.add", "id", g.ID(), "player", st.player, "strategy", strategy)
+
+		players = append(players, st.player)
+	}
+
+	return players, nil
+}
+
+// Seated reports if the player is a bot the engine could have seated at the
+// table. This is derived from the address of the bot, so it
+// still works once the bots have left the table or the engine restarted.
+func (b *Bots) Seated(state game.State, player common.Address) bool {
+	return b.seated(state.GameID, player)
+}
+
+// Count returns the number of bots sitting at the table.
+func (b *Bots) Count(gameID uuid.UUID) int {
+	return len(b.table(gameID))
+}
+
+// Start begins playing the turns of the bots in a goroutine. The function
+// is called for every action a bot takes.
+func (b *Bots) Start(fn func(ctx context.Context, a Action)) {
+	b.wg.Add(1)
+
+	go func() {
+		defer b.wg.Done()
+
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				b.check(fn)
+
+			case <-b.shutdown:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the bots and waits for the goroutine to terminate.
+func (b *Bots) Shutdown() {
+	close(b.shutdown)
+	b.wg.Wait()
+}
+
+// Roll rolls the dice for the bots at the table that haven't rolled for the
+// current round. This lets the bots roll as soon as a game is started.
+func (b *Bots) Roll(ctx context.Context, g *game.Game, fn func(ctx context.Context, a Action)) {
+	state := g.State()
+
+	for _, st := range b.table(g.ID()) {
+		cup, exists := state.Cups[st.player]
+		if !exists || cup.Outs >= state.Rules.MaxOuts() || cup.Commitment != "" {
+			continue
+		}
+
+		if err := g.RollDice(ctx, st.player); err != nil {
+			b.log.Error(ctx, "bot.rolldice", "id", g.ID(), "player", st.player, "ERROR", err)
+			continue
+		}
+
+		fn(ctx, Action{GameID: g.ID(), Player: st.player, Type: ActionRoll})
+	}
+}
+
+// check plays the turn of every bot that is up in a game.
+func (b *Bots) check(fn func(ctx context.Context, a Action)) {
+	ctx := context.Background()
+
+	b.mu.RLock()
+	gameIDs := make([]uuid.UUID, 0, len(b.seats))
+	for gameID := range b.seats {
+		gameIDs = append(gameIDs, gameID)
+	}
+	b.mu.RUnlock()
+
+	for _, gameID := range gameIDs {
+		g, err := game.Tables.Retrieve(ctx, gameID)
+		if err != nil {
+			b.leave(gameID)
+			continue
+		}
+
+		b.play(ctx, g, fn)
+	}
+}
+
+// play takes the action that is required from the bots in the game.
+func (b *Bots) play(ctx context.Context, g *game.Game, fn func(ctx context.Context, a Action)) {
+	switch g.Status() {
+	case game.StatusPlaying:
+		b.Roll(ctx, g, fn)
+
+		state := g.State()
+		for _, st := range b.table(g.ID()) {
+			if st.player == state.PlayerTurn {
+				b.move(ctx, g, st, state, fn)
+				return
+			}
+		}
+
+	case game.StatusGameOver:
+		state := g.State()
+		if !b.seated(g.ID(), state.PlayerLastWin) {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, b.bankTimeout)
+		defer cancel()
+
+		if _, _, err := g.Reconcile(ctx); err != nil {
+			b.log.Error(ctx, "bot.reconcile", "id", g.ID(), "player", state.PlayerLastWin, "ERROR", err)
+			return
+		}
+
+		fn(ctx, Action{GameID: g.ID(), Player: state.PlayerLastWin, Type: ActionReconcile})
+
+	case game.StatusReconciled, game.StatusAbandoned:
+		b.leave(g.ID())
+	}
+}
+
+// move asks the strategy for the bot's play and makes it.
+func (b *Bots) move(ctx context.Context, g *game.Game, st seat, state game.State, fn func(ctx context.Context, a Action)) {
+	m := st.strategy.Play(state, st.player)
+
+	b.log.Info(ctx, "bot.move", "id", g.ID(), "player", st.player, "call", m.Call, "number", m.Number, "suit", m.Suit)
+
+	action := Action{GameID: g.ID(), Player: st.player}
+
+	var err error
+	switch m.Call {
+	case CallLiar:
+		action.Type = ActionLiar
+		_, _, err = g.CallLiar(ctx, st.player)
+
+	case CallExact:
+		action.Type = ActionExact
+		_, _, err = g.CallExact(ctx, st.player)
+
+	default:
+		action.Type = ActionBet
+		err = g.Bet(ctx, st.player, m.Number, m.Suit)
+	}
+
+	if err != nil {
+		b.log.Error(ctx, "bot.move", "id", g.ID(), "player", st.player, "ERROR", err)
+		return
+	}
+
+	// A call ends the round, so the next round needs to be started.
+	if action.Type != ActionBet {
+		if _, err := g.NextRound(ctx); err != nil {
+			b.log.Error(ctx, "bot.nextround", "id", g.ID(), "ERROR", err)
+		}
+	}
+
+	fn(ctx, action)
+}
+
+// address returns the address of the bot in the specified seat. The address
+// is derived from the engine's address so it's owned by the engine.
+func (b *Bots) address(gameID uuid.UUID, index int) common.Address {
+	return common.BytesToAddress(crypto.Keccak256(b.engine.Bytes(), gameID[:], []byte(strconv.Itoa(index))))
+}
+
+// seated reports if the player has the address of a bot in any seat of the
+// table.
+func (b *Bots) seated(gameID uuid.UUID, player common.Address) bool {
+	for i := 0; i < game.MaxNumberPlayers; i++ {
+		if b.address(gameID, i) == player {
+			return true
+		}
+	}
+
+	return false
+}
+
+// table returns a copy of the bots sitting at the table.
+func (b *Bots) table(gameID uuid.UUID) []seat {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	seats := make([]seat, len(b.seats[gameID]))
+	copy(seats, b.seats[gameID])
+
+	return seats
+}
+
+// sit adds the bot to the table.
+func (b *Bots) sit(gameID uuid.UUID, st seat) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seats[gameID] = append(b.seats[gameID], st)
+}
+
+// stand removes the bot from the table.
+func (b *Bots) stand(gameID uuid.UUID, player common.Address) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	seats := b.seats[gameID]
+	for i, st := range seats {
+		if st.player == player {
+			b.seats[gameID] = append(seats[:i], seats[i+1:]...)
+			break
+		}
+	}
+
+	if len(b.seats[gameID]) == 0 {
+		delete(b.seats, gameID)
+	}
+}
+
+// leave removes every bot from the table.
+func (b *Bots) leave(gameID uuid.UUID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.seats, gameID)
+}
+
+// =============================================================================
+
+// Banker returns a banker that backs the seats of the bots with the engine's
+// balance in the contract. The balances of every other player are provided
+// by the specified banker. The seats of the bots are reserved and settled
+// against the engine's account, since the addresses of the bots hold no
+// balance, so every seat of a bot shares the engine's balance.
+func (b *Bots) Banker(banker game.Banker) game.Banker {
+	return botBanker{
+		Banker: banker,
+		bots:   b,
+	}
+}
+
+// botBanker provides the engine's account for the bots.
+type botBanker struct {
+	game.Banker
+	bots *Bots
+}
+
+// Account implements the game.Accounter interface. The seats of the bots are
+// found from their addresses, so they are known after the engine restarts.
+func (bb botBanker) Account(gameID uuid.UUID, player common.Address) common.Address {
+	if bb.bots.seated(gameID, player) {
+		return bb.bots.engine
+	}
+
+	return player
+}
@@ -0,0 +1,86 @@
+package bot_test
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/liarsdice/business/core/bot"
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_Strategies(t *testing.T) {
+	player := common.HexToAddress("0x8e113078adf6888b7ba84967f299f29aece24c55")
+	other := common.HexToAddress("0x0070742ff6003c3e809e78d524f0fe5dcc5ba7f7")
+
+	rules, err := game.ParseRules(game.RulesetClassic)
+	if err != nil {
+		t.Fatalf("should be able to parse the rules: %s", err)
+	}
+
+	state := game.State{
+		Status:          game.StatusPlaying,
+		Round:           1,
+		PlayerTurn:      player,
+		ExistingPlayers: []common.Address{player, other},
+		Cups: map[common.Address]game.Cup{
+			player: {Player: player, OrderIdx: 0, Dice: []int{4, 4, 4, 2, 6}},
+			other:  {Player: other, OrderIdx: 1, Dice: []int{1, 2, 3, 5, 6}},
+		},
+		Rules: rules,
+	}
+
+	strategies := map[string]bot.Strategy{
+		bot.StrategyRandom:       bot.NewRandom(game.NewSeededDicer(1)),
+		bot.StrategyProbability:  bot.Probability{},
+		bot.StrategyConservative: bot.Conservative{},
+	}
+
+	for name, strategy := range strategies {
+
+		// Every strategy must open the round with a bet that's allowed.
+		move := strategy.Play(state, player)
+		if move.Call != "" {
+			t.Fatalf("%s: expecting an opening bet; got call %q", name, move.Call)
+		}
+
+		if err := state.CheckBet(move.Number, move.Suit); err != nil {
+			t.Fatalf("%s: expecting a valid opening bet: %s", name, err)
+		}
+
+		// Every strategy must call liar when no bet can follow the last one.
+		last := state
+		last.Bets = []game.Bet{{Player: other, Number: 10, Suit: 6}}
+
+		if move := strategy.Play(last, player); move.Call != bot.CallLiar {
+			t.Fatalf("%s: expecting a liar call after the highest bet; got %+v", name, move)
+		}
+	}
+
+	// A bot holding three fours shouldn't doubt a bet of two fours.
+	state.Bets = []game.Bet{{Player: other, Number: 2, Suit: 4}}
+
+	for _, name := range []string{bot.StrategyProbability, bot.StrategyConservative} {
+		move := strategies[name].Play(state, player)
+		if move.Call != "" {
+			t.Fatalf("%s: expecting a bet after a bet the bot can see is true; got call %q", name, move.Call)
+		}
+	}
+
+	// The conservative bot stays on the suit it holds the most of.
+	if move := strategies[bot.StrategyConservative].Play(state, player); move.Suit != 4 {
+		t.Fatalf("conservative: expecting a bet on fours; got %+v", move)
+	}
+
+	// A bet on more sixes than the bot expects to be in play is called.
+	state.Bets = []game.Bet{{Player: other, Number: 6, Suit: 6}}
+
+	for _, name := range []string{bot.StrategyProbability, bot.StrategyConservative} {
+		if move := strategies[name].Play(state, player); move.Call != bot.CallLiar {
+			t.Fatalf("%s: expecting a liar call; got %+v", name, move)
+		}
+	}
+
+	if _, err := bot.ParseStrategy("bluffer", game.NewSeededDicer(1)); err == nil {
+		t.Fatalf("expecting an error for an unknown strategy")
+	}
+}
@@ -0,0 +1,192 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Represents the names of the supported strategies.
+const (
+	StrategyRandom       = "random"
+	StrategyProbability  = "probability"
+	StrategyConservative = "conservative"
+)
+
+// Represents the calls a bot can make instead of a bet.
+const (
+	CallLiar  = "liar"
+	CallExact = "exact"
+)
+
+// Move represents the play a bot makes on its turn. When Call is empty the
+// move is a bet.
+type Move struct {
+	Call   string
+	Number int
+	Suit   int
+}
+
+// Strategy represents the behaviour a bot uses to decide its play. The state
+// contains every cup in the game, but a strategy must only look at the dice
+// in the bot's own cup.
+type Strategy interface {
+	Play(state game.State, player common.Address) Move
+}
+
+// ParseStrategy returns the strategy for the specified name. An empty name
+// returns the probability strategy.
+func ParseStrategy(name string, dicer game.Dicer) (Strategy, error) {
+	switch name {
+	case "", StrategyProbability:
+		return Probability{}, nil
+
+	case StrategyRandom:
+		return NewRandom(dicer), nil
+
+	case StrategyConservative:
+		return Conservative{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown strategy %q", name)
+}
+
+// =============================================================================
+
+// Random makes a random bet from the smallest bets that are allowed and
+// calls liar one time out of four.
+type Random struct {
+	dicer game.Dicer
+}
+
+// NewRandom constructs a random strategy that uses the dicer for its choices.
+func NewRandom(dicer game.Dicer) Random {
+	return Random{dicer: dicer}
+}
+
+// Play implements the Strategy interface.
+func (r Random) Play(state game.State, player common.Address) Move {
+	bets := validBets(state)
+
+	if len(state.Bets) > 0 && (len(bets) == 0 || r.dicer.Roll(4) == 1) {
+		return Move{Call: CallLiar}
+	}
+
+	choices := min(len(bets), 6)
+	if choices == 0 {
+		return Move{Call: CallLiar}
+	}
+
+	bet := bets[r.dicer.Roll(choices)-1]
+
+	return Move{Number: bet.Number, Suit: bet.Suit}
+}
+
+// =============================================================================
+
+// Probability makes the bet that is most likely to be true and calls liar
+// when the last bet is more likely false than true.
+type Probability struct{}
+
+// Play implements the Strategy interface.
+func (Probability) Play(state game.State, player common.Address) Move {
+	if len(state.Bets) > 0 {
+		last := state.Bets[len(state.Bets)-1]
+
+		if state.Rules.SpotOn && state.Odds(player, last.Number, last.Suit).Exactly > 0.5 {
+			return Move{Call: CallExact}
+		}
+
+		if state.Odds(player, last.Number, last.Suit).AtLeast < 0.5 {
+			return Move{Call: CallLiar}
+		}
+	}
+
+	var best game.Bet
+	var bestOdds float64
+	for _, bet := range validBets(state) {
+		if odds := state.Odds(player, bet.Number, bet.Suit).AtLeast; odds > bestOdds {
+			best, bestOdds = bet, odds
+		}
+	}
+
+	if bestOdds == 0 {
+		return Move{Call: CallLiar}
+	}
+
+	return Move{Number: best.Number, Suit: best.Suit}
+}
+
+// =============================================================================
+
+// Conservative only bets on the suit it holds the most of and calls liar as
+// soon as the last bet claims more dice than it expects to be in play.
+type Conservative struct{}
+
+// Play implements the Strategy interface.
+func (Conservative) Play(state game.State, player common.Address) Move {
+	bets := validBets(state)
+
+	if len(state.Bets) > 0 {
+		last := state.Bets[len(state.Bets)-1]
+
+		if float64(last.Number) > state.Odds(player, last.Number, last.Suit).Expected {
+			return Move{Call: CallLiar}
+		}
+	}
+
+	suit := favorite(state, player)
+
+	for _, bet := range bets {
+		if bet.Suit != suit {
+			continue
+		}
+
+		if float64(bet.Number) > state.Odds(player, bet.Number, bet.Suit).Expected {
+			break
+		}
+
+		return Move{Number: bet.Number, Suit: bet.Suit}
+	}
+
+	if len(state.Bets) > 0 || len(bets) == 0 {
+		return Move{Call: CallLiar}
+	}
+
+	return Move{Number: bets[0].Number, Suit: bets[0].Suit}
+}
+
+// =============================================================================
+
+// validBets returns the bets that can be made next in the round ordered by
+// number and then by suit.
+func validBets(state game.State) []game.Bet {
+	var bets []game.Bet
+	for number := 1; number <= state.DiceInPlay(); number++ {
+		for suit := 1; suit <= game.NumberOfSuits; suit++ {
+			if err := state.CheckBet(number, suit); err == nil {
+				bets = append(bets, game.Bet{Number: number, Suit: suit})
+			}
+		}
+	}
+
+	return bets
+}
+
+// favorite returns the suit the player holds the most of. Wild ones are
+// never the favorite since they count towards every suit.
+func favorite(state game.State, player common.Address) int {
+	suit, most := 2, -1
+	for s := 1; s <= game.NumberOfSuits; s++ {
+		if s == 1 && state.Wilds() {
+			continue
+		}
+
+		if known := state.Odds(player, 0, s).Known; known > most {
+			suit, most = s, known
+		}
+	}
+
+	return suit
+}
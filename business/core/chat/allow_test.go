@@ -0,0 +1,39 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_AllowPrunesIdlePlayers(t *testing.T) {
+	player1 := common.HexToAddress("0x8e113078adf6888b7ba84967f299f29aece24c55")
+	player2 := common.HexToAddress("0x0070742ff6003c3e809e78d524f0fe5dcc5ba7f7")
+
+	core := NewCore(nil, nil)
+	now := time.Now().UTC()
+
+	if !core.allow(player1, now) || !core.allow(player2, now) {
+		t.Fatal("expecting the players to be allowed to send a message")
+	}
+
+	if len(core.sent) != 2 {
+		t.Fatalf("expecting 2 players tracked; got %d", len(core.sent))
+	}
+
+	// Once the window passes, the players who stopped sending are removed.
+	later := now.Add(rateWindow)
+
+	if !core.allow(player2, later) {
+		t.Fatal("expecting player2 to be allowed to send a message")
+	}
+
+	if _, exists := core.sent[player1]; exists {
+		t.Fatal("expecting player1 to be removed after the window")
+	}
+
+	if len(core.sent) != 1 {
+		t.Fatalf("expecting 1 player tracked; got %d", len(core.sent))
+	}
+}
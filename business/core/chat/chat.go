@@ -0,0 +1,190 @@
+// Package chat provides support for the players at a table to send messages
+// to each other while they play.
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ardanlabs/liarsdice/foundation/validate"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// ErrRateLimited is returned when a player sends messages too quickly.
+var ErrRateLimited = errors.New("sending messages too quickly")
+
+// Represents the limits placed on the chat of a game.
+const (
+	MaxLength   = 200              // The most characters a message can have.
+	HistorySize = 100              // The number of messages kept for a game.
+	rateLimit   = 5                // The messages a player can send in the rate window.
+	rateWindow  = 10 * time.Second // The window the rate limit is applied over.
+)
+
+// Storer interface declares the behaviour this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, msg Message) error
+	Trim(ctx context.Context, gameID uuid.UUID, keep int) error
+	QueryByGame(ctx context.Context, gameID uuid.UUID, limit int) ([]Message, error)
+}
+
+// Message represents a message sent to the table of a game.
+type Message struct {
+	ID          uuid.UUID
+	GameID      uuid.UUID
+	Player      common.Address
+	Text        string
+	DateCreated time.Time
+}
+
+// NewMessage contains the information needed to send a message.
+type NewMessage struct {
+	GameID uuid.UUID
+	Player common.Address
+	Text   string
+}
+
+// Core manages the set of APIs for chat access.
+type Core struct {
+	log    *logger.Logger
+	storer Storer
+	mu     sync.Mutex
+	sent   map[common.Address][]time.Time
+	pruned time.Time // When the players who stopped sending were last removed.
+}
+
+// NewCore constructs a core for chat api access.
+func NewCore(log *logger.Logger, storer Storer) *Core {
+	return &Core{
+		log:    log,
+		storer: storer,
+		sent:   make(map[common.Address][]time.Time),
+	}
+}
+
+// Send validates and stores a message sent to the table of a game. Words
+// on the profanity list are masked. Only the most recent messages of a game
+// are kept.
+func (c *Core) Send(ctx context.Context, nm NewMessage) (Message, error) {
+	text, err := Clean(nm.Text)
+	if err != nil {
+		return Message{}, validate.NewFieldsError("message", err)
+	}
+
+	now := time.Now().UTC()
+
+	if !c.allow(nm.Player, now) {
+		return Message{}, ErrRateLimited
+	}
+
+	msg := Message{
+		ID:          uuid.New(),
+		GameID:      nm.GameID,
+		Player:      nm.Player,
+		Text:        Filter(text),
+		DateCreated: now,
+	}
+
+	if err := c.storer.Create(ctx, msg); err != nil {
+		return Message{}, fmt.Errorf("create: %w", err)
+	}
+
+	if err := c.storer.Trim(ctx, msg.GameID, HistorySize); err != nil {
+		return Message{}, fmt.Errorf("trim: %w", err)
+	}
+
+	c.log.Info(ctx, "chat.send", "id", msg.GameID, "player", msg.Player)
+
+	return msg, nil
+}
+
+// QueryByGame returns the messages kept for a game from oldest to newest.
+func (c *Core) QueryByGame(ctx context.Context, gameID uuid.UUID) ([]Message, error) {
+	msgs, err := c.storer.QueryByGame(ctx, gameID, HistorySize)
+	if err != nil {
+		return nil, fmt.Errorf("query: game[%s]: %w", gameID, err)
+	}
+
+	return msgs, nil
+}
+
+// allow reports if the player can send a message at the specified time and
+// records the message when they can.
+func (c *Core) allow(player common.Address, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prune(now)
+
+	// Drop the messages that are outside the window.
+	var sent []time.Time
+	for _, t := range c.sent[player] {
+		if now.Sub(t) < rateWindow {
+			sent = append(sent, t)
+		}
+	}
+
+	if len(sent) >= rateLimit {
+		c.sent[player] = sent
+		return false
+	}
+
+	c.sent[player] = append(sent, now)
+
+	return true
+}
+
+// prune removes the players who haven't sent a message within the window, so
+// the players who stopped chatting aren't kept for the life of the engine.
+// The players are checked at most once per window. The caller must hold the
+// lock.
+func (c *Core) prune(now time.Time) {
+	if now.Sub(c.pruned) < rateWindow {
+		return
+	}
+
+	for player, sent := range c.sent {
+		if len(sent) == 0 || now.Sub(sent[len(sent)-1]) >= rateWindow {
+			delete(c.sent, player)
+		}
+	}
+
+	c.pruned = now
+}
+
+// =============================================================================
+
+// Clean keeps a message to a single line without surrounding whitespace and
+// checks its length. Tabs and line breaks become spaces and other control
+// characters are removed.
+func Clean(text string) (string, error) {
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, text)
+
+	text = strings.TrimSpace(text)
+
+	switch n := utf8.RuneCountInString(text); {
+	case n == 0:
+		return "", errors.New("message can't be empty")
+	case n > MaxLength:
+		return "", fmt.Errorf("message can't be longer than %d characters: length[%d]", MaxLength, n)
+	}
+
+	return text, nil
+}
@@ -0,0 +1,116 @@
+package chat_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/liarsdice/business/core/chat"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ardanlabs/liarsdice/foundation/validate"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+func Test_Filter(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "nice bet", want: "nice bet"},
+		{text: "what the Fuck", want: "what the ****"},
+		{text: "fucking liar!", want: "******* liar!"},
+		{text: "pass the class, you ass", want: "pass the class, you ***"},
+		{text: "scrap that, DAMN", want: "scrap that, ****"},
+	}
+
+	for _, tt := range tests {
+		if got := chat.Filter(tt.text); got != tt.want {
+			t.Fatalf("expecting %q to be filtered to %q; got %q", tt.text, tt.want, got)
+		}
+	}
+}
+
+func Test_Clean(t *testing.T) {
+	text, err := chat.Clean("  good\tluck\x00\n ")
+	if err != nil {
+		t.Fatalf("unexpected error cleaning the message: %s", err)
+	}
+
+	if text != "good luck" {
+		t.Fatalf("expecting the message on a single line; got %q", text)
+	}
+
+	if _, err := chat.Clean(" \n "); err == nil {
+		t.Fatal("expecting an error for an empty message")
+	}
+
+	if _, err := chat.Clean(strings.Repeat("a", chat.MaxLength+1)); err == nil {
+		t.Fatal("expecting an error for a message that is too long")
+	}
+}
+
+func Test_Send(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(&buf, logger.LevelInfo, "TEST", func(context.Context) string { return "00000000-0000-0000-0000-000000000000" })
+
+	store := &memStore{}
+	core := chat.NewCore(log, store)
+
+	ctx := context.Background()
+	gameID := uuid.New()
+	player := common.HexToAddress("0x8e113078adf6888b7ba84967f299f29aece24c55")
+
+	if _, err := core.Send(ctx, chat.NewMessage{GameID: gameID, Player: player, Text: "   "}); !validate.IsFieldErrors(err) {
+		t.Fatalf("expecting field errors for an empty message; got %v", err)
+	}
+
+	msg, err := core.Send(ctx, chat.NewMessage{GameID: gameID, Player: player, Text: "oh shit"})
+	if err != nil {
+		t.Fatalf("unexpected error sending a message: %s", err)
+	}
+
+	if msg.Text != "oh ****" {
+		t.Fatalf("expecting the message to be filtered; got %q", msg.Text)
+	}
+
+	// The player can send a burst of messages before being rate limited.
+	var limited bool
+	for i := 0; i < 10; i++ {
+		if _, err := core.Send(ctx, chat.NewMessage{GameID: gameID, Player: player, Text: "hi"}); errors.Is(err, chat.ErrRateLimited) {
+			limited = true
+			break
+		}
+	}
+
+	if !limited {
+		t.Fatal("expecting the player to be rate limited")
+	}
+
+	if store.trimmed != chat.HistorySize {
+		t.Fatalf("expecting the history to be trimmed to %d messages; got %d", chat.HistorySize, store.trimmed)
+	}
+}
+
+// =============================================================================
+
+type memStore struct {
+	msgs    []chat.Message
+	trimmed int
+}
+
+func (s *memStore) Create(ctx context.Context, msg chat.Message) error {
+	s.msgs = append(s.msgs, msg)
+	return nil
+}
+
+func (s *memStore) Trim(ctx context.Context, gameID uuid.UUID, keep int) error {
+	s.trimmed = keep
+	return nil
+}
+
+func (s *memStore) QueryByGame(ctx context.Context, gameID uuid.UUID, limit int) ([]chat.Message, error) {
+	return s.msgs, nil
+}
@@ -0,0 +1,75 @@
+package chat
+
+import (
+	"strings"
+	"unicode"
+)
+
+// profanity is the list of words that are masked in messages.
+var profanity = map[string]bool{
+	"arse":     true,
+	"ass":      true,
+	"asshole":  true,
+	"bastard":  true,
+	"bitch":    true,
+	"bollocks": true,
+	"bullshit": true,
+	"crap":     true,
+	"cunt":     true,
+	"damn":     true,
+	"dick":     true,
+	"piss":     true,
+	"prick":    true,
+	"slut":     true,
+	"twat":     true,
+	"wanker":   true,
+	"whore":    true,
+}
+
+// profanityStems are masked at the start of any word, so the different
+// forms of the word are caught.
+var profanityStems = []string{"fuck", "shit", "cunt", "bitch"}
+
+// Filter masks the words of a message that are on the profanity list. The
+// words are matched without regard to case and only whole words are masked,
+// so words like class or scrap are left alone.
+func Filter(text string) string {
+	runes := []rune(text)
+
+	for start := 0; start < len(runes); {
+		if !unicode.IsLetter(runes[start]) {
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(runes) && unicode.IsLetter(runes[end]) {
+			end++
+		}
+
+		if profane(strings.ToLower(string(runes[start:end]))) {
+			for i := start; i < end; i++ {
+				runes[i] = '*'
+			}
+		}
+
+		start = end
+	}
+
+	return string(runes)
+}
+
+// profane reports if the lowercase word is on the profanity list.
+func profane(word string) bool {
+	if profanity[word] {
+		return true
+	}
+
+	for _, stem := range profanityStems {
+		if strings.HasPrefix(word, stem) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,116 @@
+// Package chatdb contains chat related CRUD functionality.
+package chatdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ardanlabs/liarsdice/business/core/chat"
+	"github.com/ardanlabs/liarsdice/business/data/sqldb"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for chat database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create adds a message to the db.
+func (s *Store) Create(ctx context.Context, msg chat.Message) error {
+	q := `
+    INSERT INTO game_chat
+        (chat_id, game_id, player, message, date_created)
+    VALUES
+        (:chat_id, :game_id, :player, :message, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBMessage(msg)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Trim removes all but the most recent messages of a game from the db.
+func (s *Store) Trim(ctx context.Context, gameID uuid.UUID, keep int) error {
+	data := struct {
+		ID   string `db:"game_id"`
+		Keep int    `db:"keep"`
+	}{
+		ID:   gameID.String(),
+		Keep: keep,
+	}
+
+	q := `
+    DELETE FROM
+        game_chat
+    WHERE
+        game_id = :game_id AND
+        chat_id NOT IN (
+            SELECT
+                chat_id
+            FROM
+                game_chat
+            WHERE
+                game_id = :game_id
+            ORDER BY
+                date_created DESC
+            LIMIT :keep
+        )`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByGame gets the most recent messages of a game from the db, from
+// oldest to newest.
+func (s *Store) QueryByGame(ctx context.Context, gameID uuid.UUID, limit int) ([]chat.Message, error) {
+	data := struct {
+		ID    string `db:"game_id"`
+		Limit int    `db:"limit"`
+	}{
+		ID:    gameID.String(),
+		Limit: limit,
+	}
+
+	q := `
+	SELECT
+		*
+	FROM (
+		SELECT
+			chat_id,
+			game_id,
+			player,
+			message,
+			date_created
+		FROM
+			game_chat
+		WHERE
+			game_id = :game_id
+		ORDER BY
+			date_created DESC
+		LIMIT :limit
+	) AS recent
+	ORDER BY
+		date_created`
+
+	var dbMsgs []dbMessage
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbMsgs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreMessageSlice(dbMsgs), nil
+}
@@ -0,0 +1,46 @@
+package chatdb
+
+import (
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/core/chat"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+type dbMessage struct {
+	ID          uuid.UUID `db:"chat_id"`
+	GameID      uuid.UUID `db:"game_id"`
+	Player      string    `db:"player"`
+	Message     string    `db:"message"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBMessage(msg chat.Message) dbMessage {
+	return dbMessage{
+		ID:          msg.ID,
+		GameID:      msg.GameID,
+		Player:      msg.Player.String(),
+		Message:     msg.Text,
+		DateCreated: msg.DateCreated,
+	}
+}
+
+func toCoreMessage(dbMsg dbMessage) chat.Message {
+	return chat.Message{
+		ID:          dbMsg.ID,
+		GameID:      dbMsg.GameID,
+		Player:      common.HexToAddress(dbMsg.Player),
+		Text:        dbMsg.Message,
+		DateCreated: dbMsg.DateCreated,
+	}
+}
+
+func toCoreMessageSlice(dbMsgs []dbMessage) []chat.Message {
+	msgs := make([]chat.Message, len(dbMsgs))
+	for i, dbMsg := range dbMsgs {
+		msgs[i] = toCoreMessage(dbMsg)
+	}
+
+	return msgs
+}
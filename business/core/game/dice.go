@@ -0,0 +1,71 @@
+package game
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	mrand "math/rand"
+	"sync"
+)
+
+// Dicer represents the source of randomness used by a game to generate the
+// server seeds the dice are derived from and to pick the player who starts.
+type Dicer interface {
+	Roll(sides int) int
+}
+
+// =============================================================================
+
+// CryptoDicer provides dice values from the crypto/rand package. This is the
+// implementation that should be used in production.
+type CryptoDicer struct{}
+
+// NewCryptoDicer constructs a dicer that uses the crypto/rand package.
+func NewCryptoDicer() *CryptoDicer {
+	return &CryptoDicer{}
+}
+
+// Roll returns a random value between 1 and the specified number of sides.
+func (*CryptoDicer) Roll(sides int) int {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(sides)))
+	if err != nil {
+
+		// If the operating system can't provide randomness there is no
+		// safe way for the game to continue.
+		panic(fmt.Sprintf("crypto dicer: %s", err))
+	}
+
+	return int(n.Int64()) + 1
+}
+
+// =============================================================================
+
+// SeededDicer provides a deterministic sequence of dice values based on a
+// seed. This is used for testing and for replaying a reported game.
+type SeededDicer struct {
+	seed int64
+	mu   sync.Mutex
+	rnd  *mrand.Rand
+}
+
+// NewSeededDicer constructs a dicer that will produce the same sequence of
+// values for the same seed.
+func NewSeededDicer(seed int64) *SeededDicer {
+	return &SeededDicer{
+		seed: seed,
+		rnd:  mrand.New(mrand.NewSource(seed)),
+	}
+}
+
+// Seed returns the seed that was used to construct the dicer.
+func (sd *SeededDicer) Seed() int64 {
+	return sd.seed
+}
+
+// Roll returns the next value between 1 and the specified number of sides.
+func (sd *SeededDicer) Roll(sides int) int {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	return sd.rnd.Intn(sides) + 1
+}
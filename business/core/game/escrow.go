@@ -0,0 +1,135 @@
+package game
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// Escrow maintains the antes reserved for the seats players hold in games.
+var Escrow = newEscrow()
+
+// escrow represents the ledger of antes reserved by the engine. A player's
+// balance in the contract can back many games, so the ante for every open
+// seat is reserved until the game is reconciled or the player leaves.
+type escrow struct {
+	reservations map[common.Address]map[uuid.UUID]*big.Float
+	mu           sync.Mutex
+}
+
+func newEscrow() *escrow {
+	return &escrow{
+		reservations: make(map[common.Address]map[uuid.UUID]*big.Float),
+	}
+}
+
+// Reserved returns the total amount in GWei reserved for the player.
+func (e *escrow) Reserved(player common.Address) *big.Float {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.reserved(player)
+}
+
+// Available returns the amount in GWei of the specified balance that is not
+// reserved for any game.
+func (e *escrow) Available(player common.Address, balanceGWei *big.Float) *big.Float {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return new(big.Float).Sub(balanceGWei, e.reserved(player))
+}
+
+// Reserve adds the amount to what is held for the player under the specified
+// key, like the id of a game or a tournament, if the player's available
+// balance can cover it. An account backing many seats in a game holds the
+// ante for each of them.
+func (e *escrow) Reserve(key uuid.UUID, player common.Address, balanceGWei *big.Float, amountGWei *big.Float) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	available := new(big.Float).Sub(balanceGWei, e.reserved(player))
+
+	// If comparison is negative, the player has no available balance.
+	if available.Cmp(amountGWei) < 0 {
+		return fmt.Errorf("player [%s] does not have enough available balance to play, available[%v]", player, available)
+	}
+
+	held := new(big.Float)
+	if amount, exists := e.reservations[player][key]; exists {
+		held.Set(amount)
+	}
+
+	e.hold(key, player, held.Add(held, amountGWei))
+
+	return nil
+}
+
+// Release takes the amount back from what is held for the player under the
+// specified key. The reservation is removed once nothing is held.
+func (e *escrow) Release(key uuid.UUID, player common.Address, amountGWei *big.Float) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	amount, exists := e.reservations[player][key]
+	if !exists {
+		return
+	}
+
+	held := new(big.Float).Sub(amount, amountGWei)
+	if held.Sign() <= 0 {
+		e.remove(key, player)
+		return
+	}
+
+	e.hold(key, player, held)
+}
+
+// ReleaseAll removes the reservations held under the specified key.
+func (e *escrow) ReleaseAll(key uuid.UUID) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for player := range e.reservations {
+		e.remove(key, player)
+	}
+}
+
+// hold sets the amount held for the player under the specified key without
+// checking the balance. This is used when games are loaded from the store.
+// The caller must hold the lock.
+func (e *escrow) hold(key uuid.UUID, player common.Address, amountGWei *big.Float) {
+	games, exists := e.reservations[player]
+	if !exists {
+		games = make(map[uuid.UUID]*big.Float)
+		e.reservations[player] = games
+	}
+
+	games[key] = new(big.Float).Set(amountGWei)
+}
+
+// remove deletes the reservation and the player once nothing is reserved.
+func (e *escrow) remove(gameID uuid.UUID, player common.Address) {
+	games, exists := e.reservations[player]
+	if !exists {
+		return
+	}
+
+	delete(games, gameID)
+	if len(games) == 0 {
+		delete(e.reservations, player)
+	}
+}
+
+// reserved returns the total amount reserved for the player.
+func (e *escrow) reserved(player common.Address) *big.Float {
+	total := new(big.Float)
+	for _, amount := range e.reservations[player] {
+		total.Add(total, amount)
+	}
+
+	return total
+}
@@ -0,0 +1,272 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// Represents the types of events that are recorded for a game.
+const (
+	EventNew       = "new"
+	EventJoin      = "join"
+	EventLeave     = "leave"
+	EventStart     = "start"
+	EventRoll      = "roll"
+	EventBet       = "bet"
+	EventTurn      = "turn"
+	EventOut       = "out"
+	EventLiar      = "liar"
+	EventExact     = "exact"
+	EventForfeit   = "forfeit"
+	EventNextRound = "nextround"
+	EventReconcile = "reconcile"
+	EventAbandon   = "abandon"
+)
+
+// Event represents a change that was made to a game. Events are recorded in
+// sequence order and contain the outcome of every command, so the state of a
+// game can be rebuilt without the randomness used to play it.
+type Event struct {
+	GameID             uuid.UUID
+	Sequence           int
+	Type               string
+	Round              int
+	Player             common.Address // The player who caused the event.
+	Date               time.Time
+	Timeout            bool           // The event was caused by an expired turn.
+	Turn               common.Address // The player whose turn it is after the event.
+	Number             int            // The number of a bet.
+	Suit               int            // The suit of a bet.
+	Dice               []int          // The dice that were rolled.
+	ClientSeed         string         // The client seed used for the roll.
+	Commitment         string         // The commitment for the roll.
+	Outs               int            // The outs applied to the player.
+	Winner             common.Address // The winner of the round.
+	Loser              common.Address // The loser of the round.
+	Status             string         // The status of the game after a new round.
+	Palifico           bool           // The new round is a palifico round.
+	AnteUSD            float64
+	Rules              Rules
+	ServerSeed         string // The server seed revealed when a round ends, or the secret seed for a new game.
+	ServerSeedHash     string
+	NextServerSeedHash string
+	NextServerSeed     string // The secret seed committed for the next round. Only kept by the engine.
+	Balances           []BalanceFmt
+}
+
+// ServerSeeds represents the secret server seeds recorded with an event. They
+// are stored apart from the events, so only the engine can read them before
+// the rounds they are used for are over.
+type ServerSeeds struct {
+	Sequence       int
+	ServerSeed     string
+	NextServerSeed string
+}
+
+// Apply rebuilds the state of a game by replaying the specified events in
+// sequence order. The first event must create the game.
+func Apply(events []Event) (State, error) {
+	return replay(events, nil)
+}
+
+// replay applies the events in sequence order, calling the specified function
+// after each event has been applied to the state.
+func replay(events []Event, fn func(state *State, e Event)) (State, error) {
+	var state State
+
+	for i, e := range events {
+		if e.Sequence != i+1 {
+			return State{}, fmt.Errorf("event out of sequence: sequence[%d] expected[%d]", e.Sequence, i+1)
+		}
+
+		if i == 0 && e.Type != EventNew {
+			return State{}, fmt.Errorf("first event must create the game: type[%s]", e.Type)
+		}
+
+		if err := state.apply(e); err != nil {
+			return State{}, fmt.Errorf("apply: sequence[%d]: %w", e.Sequence, err)
+		}
+		state.Sequence = e.Sequence
+
+		if fn != nil {
+			fn(&state, e)
+		}
+	}
+
+	return state, nil
+}
+
+// apply changes the state based on the specified event.
+func (s *State) apply(e Event) error {
+	switch e.Type {
+	case EventNew:
+		*s = State{
+			GameID:             e.GameID,
+			GameName:           e.GameID.String(),
+			DateCreated:        e.Date,
+			AnteUSD:            e.AnteUSD,
+			Status:             StatusNewGame,
+			Cups:               make(map[common.Address]Cup),
+			Rules:              e.Rules.withSeats(),
+			ServerSeedHash:     e.ServerSeedHash,
+			NextServerSeedHash: e.NextServerSeedHash,
+		}
+
+	case EventJoin:
+		s.Cups[e.Player] = Cup{
+			Player:   e.Player,
+			OrderIdx: len(s.ExistingPlayers),
+			Dice:     make([]int, numberOfDice),
+		}
+		s.ExistingPlayers = append(s.ExistingPlayers, e.Player)
+		s.Balances = append(s.Balances, e.Balances...)
+
+	case EventLeave:
+		cup, exists := s.Cups[e.Player]
+		if !exists {
+			return fmt.Errorf("player [%s] does not exist in the game", e.Player)
+		}
+
+		// A player who leaves before the game starts gives up their seat.
+		if s.Status == StatusNewGame {
+			delete(s.Cups, e.Player)
+			s.ExistingPlayers = removePlayer(s.ExistingPlayers, e.Player)
+
+			for i, player := range s.ExistingPlayers {
+				cup := s.Cups[player]
+				cup.OrderIdx = i
+				s.Cups[player] = cup
+			}
+
+			balances := make([]BalanceFmt, 0, len(s.Balances))
+			for _, balance := range s.Balances {
+				if balance.Player != e.Player {
+					balances = append(balances, balance)
+				}
+			}
+			s.Balances = balances
+
+			break
+		}
+
+		cup.Outs = s.Rules.MaxOuts()
+		cup.Dice = make([]int, s.Rules.dice(cup.Outs))
+		s.Cups[e.Player] = cup
+
+		s.Bets = removeBets(s.Bets, e.Player)
+		s.Status = e.Status
+		s.PlayerTurn = e.Turn
+
+		if e.Status == StatusGameOver {
+			s.Bets = []Bet{}
+			s.PlayerLastWin = e.Winner
+		}
+
+	case EventStart:
+		s.Status = StatusPlaying
+		s.Round = 1
+		s.PlayerTurn = e.Turn
+
+	case EventRoll:
+		cup, exists := s.Cups[e.Player]
+		if !exists {
+			return fmt.Errorf("player [%s] does not exist in the game", e.Player)
+		}
+
+		cup.Dice = make([]int, len(e.Dice))
+		copy(cup.Dice, e.Dice)
+		cup.ClientSeed = e.ClientSeed
+		cup.Commitment = e.Commitment
+		s.Cups[e.Player] = cup
+
+	case EventBet:
+		s.Bets = append(s.Bets, Bet{
+			Player: e.Player,
+			Number: e.Number,
+			Suit:   e.Suit,
+		})
+		s.PlayerTurn = e.Turn
+
+	case EventTurn:
+		s.PlayerTurn = e.Turn
+
+	case EventOut:
+		cup, exists := s.Cups[e.Player]
+		if !exists {
+			return fmt.Errorf("player [%s] does not exist in the game", e.Player)
+		}
+
+		cup.Outs = e.Outs
+		cup.Dice = make([]int, s.Rules.dice(e.Outs))
+		s.Cups[e.Player] = cup
+
+		var activePlayers int
+		for _, cup := range s.Cups {
+			if cup.Outs < s.Rules.MaxOuts() {
+				activePlayers++
+			}
+		}
+
+		if activePlayers == 1 {
+			s.Status = StatusRoundOver
+		}
+
+	case EventLiar, EventExact, EventForfeit:
+		cup, exists := s.Cups[e.Loser]
+		if !exists {
+			return fmt.Errorf("player [%s] does not exist in the game", e.Loser)
+		}
+
+		if cup.Outs < s.Rules.MaxOuts() {
+			cup.Outs++
+		}
+		s.Cups[e.Loser] = cup
+
+		s.Reveal = newReveal(s.Round, s.ExistingPlayers, s.Cups, s.Bets, s.Rules, s.Palifico, e.Type == EventExact, e.Winner, e.Loser)
+
+		s.Status = StatusRoundOver
+		s.PlayerLastOut = e.Loser
+		s.PlayerLastWin = e.Winner
+		s.PlayerTurn = e.Turn
+		s.ServerSeed = e.ServerSeed
+
+	case EventNextRound:
+		for player, cup := range s.Cups {
+			cup.Dice = make([]int, s.Rules.dice(cup.Outs))
+			cup.ClientSeed = ""
+			cup.Commitment = ""
+			s.Cups[player] = cup
+		}
+
+		s.Bets = []Bet{}
+		s.Status = e.Status
+
+		if e.Status == StatusGameOver {
+			break
+		}
+
+		s.Round = e.Round
+		s.PlayerTurn = e.Turn
+		s.Palifico = e.Palifico
+		s.ServerSeed = ""
+		s.ServerSeedHash = e.ServerSeedHash
+		s.NextServerSeedHash = e.NextServerSeedHash
+
+	case EventReconcile:
+		s.Status = StatusReconciled
+		s.Round = e.Round
+		s.Balances = make([]BalanceFmt, len(e.Balances))
+		copy(s.Balances, e.Balances)
+
+	case EventAbandon:
+		s.Status = StatusAbandoned
+
+	default:
+		return fmt.Errorf("unknown event type %q", e.Type)
+	}
+
+	return nil
+}
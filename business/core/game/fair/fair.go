@@ -0,0 +1,108 @@
+// Package fair provides support for provably fair dice rolling using a
+// commit-reveal protocol.
+//
+// At the start of a round the engine publishes the hash of a secret server
+// seed. Each player can provide a client seed that is mixed into their roll.
+// The dice for a cup are derived from an HMAC of the client seed and a nonce
+// that is keyed by the server seed. The engine also publishes a commitment
+// for each cup's dice. Once the round is over the server seed is revealed and
+// any client can recalculate the dice and check the commitments.
+package fair
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// Cup represents the information required to verify a single cup.
+type Cup struct {
+	Player     common.Address
+	ClientSeed string
+	Dice       []int
+	Commitment string
+}
+
+// Hash returns the commitment for the specified server seed.
+func Hash(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Nonce returns the value that makes a roll unique for a player in a round
+// of a game.
+func Nonce(gameID uuid.UUID, round int, player common.Address) string {
+	return fmt.Sprintf("%s:%d:%s", gameID, round, player.Hex())
+}
+
+// Roll derives the specified number of dice from the seeds and nonce. The
+// same inputs will always produce the same dice.
+func Roll(serverSeed string, clientSeed string, nonce string, n int) []int {
+	dice := make([]int, 0, n)
+
+	for counter := 0; len(dice) < n; counter++ {
+		mac := hmac.New(sha256.New, []byte(serverSeed))
+		mac.Write([]byte(fmt.Sprintf("%s:%s:%d", clientSeed, nonce, counter)))
+
+		for _, b := range mac.Sum(nil) {
+
+			// Reject values that would bias the result since 256 is
+			// not a multiple of 6.
+			if b >= 252 {
+				continue
+			}
+
+			dice = append(dice, int(b%6)+1)
+			if len(dice) == n {
+				break
+			}
+		}
+	}
+
+	return dice
+}
+
+// Commit returns the commitment for a player's dice.
+func Commit(serverSeed string, player common.Address, dice []int) string {
+	values := make([]string, len(dice))
+	for i, d := range dice {
+		values[i] = strconv.Itoa(d)
+	}
+
+	data := fmt.Sprintf("%s:%s:%s", serverSeed, player.Hex(), strings.Join(values, ","))
+	sum := sha256.Sum256([]byte(data))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify checks the revealed server seed against its published hash and that
+// the cup's dice and commitment were derived from the seeds.
+func Verify(gameID uuid.UUID, round int, serverSeed string, serverSeedHash string, cup Cup) error {
+	if serverSeed == "" {
+		return errors.New("server seed has not been revealed")
+	}
+
+	if Hash(serverSeed) != serverSeedHash {
+		return fmt.Errorf("server seed does not match its hash: hash[%s]", serverSeedHash)
+	}
+
+	if Commit(serverSeed, cup.Player, cup.Dice) != cup.Commitment {
+		return fmt.Errorf("dice do not match the commitment for player [%s]", cup.Player)
+	}
+
+	exp := Roll(serverSeed, cup.ClientSeed, Nonce(gameID, round, cup.Player), len(cup.Dice))
+	for i := range exp {
+		if exp[i] != cup.Dice[i] {
+			return fmt.Errorf("dice were not derived from the seeds for player [%s]: exp%v got%v", cup.Player, exp, cup.Dice)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package fair_test
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/liarsdice/business/core/game/fair"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+func Test_RollVerify(t *testing.T) {
+	gameID := uuid.New()
+	player := common.HexToAddress("0x8e113078adf6888b7ba84967f299f29aece24c55")
+
+	const serverSeed = "3f1c9b1f2a6d"
+	const clientSeed = "my lucky seed"
+	const round = 2
+
+	dice := fair.Roll(serverSeed, clientSeed, fair.Nonce(gameID, round, player), 5)
+	if len(dice) != 5 {
+		t.Fatalf("expecting 5 dice; got %d", len(dice))
+	}
+
+	for _, d := range dice {
+		if d < 1 || d > 6 {
+			t.Fatalf("expecting dice between 1 and 6; got %v", dice)
+		}
+	}
+
+	again := fair.Roll(serverSeed, clientSeed, fair.Nonce(gameID, round, player), 5)
+	for i := range dice {
+		if dice[i] != again[i] {
+			t.Fatalf("expecting the same dice for the same seeds; got %v and %v", dice, again)
+		}
+	}
+
+	cup := fair.Cup{
+		Player:     player,
+		ClientSeed: clientSeed,
+		Dice:       dice,
+		Commitment: fair.Commit(serverSeed, player, dice),
+	}
+
+	if err := fair.Verify(gameID, round, serverSeed, fair.Hash(serverSeed), cup); err != nil {
+		t.Fatalf("unexpected error verifying cup: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// Tampering with any value must be detected.
+
+	if err := fair.Verify(gameID, round, "other seed", fair.Hash(serverSeed), cup); err == nil {
+		t.Fatal("expecting error verifying with the wrong server seed")
+	}
+
+	if err := fair.Verify(gameID, round+1, serverSeed, fair.Hash(serverSeed), cup); err == nil {
+		t.Fatal("expecting error verifying with the wrong round")
+	}
+
+	bad := cup
+	bad.Dice = append([]int{}, dice...)
+	bad.Dice[0] = bad.Dice[0]%6 + 1
+	bad.Commitment = fair.Commit(serverSeed, player, bad.Dice)
+	if err := fair.Verify(gameID, round, serverSeed, fair.Hash(serverSeed), bad); err == nil {
+		t.Fatal("expecting error verifying dice not derived from the seeds")
+	}
+}
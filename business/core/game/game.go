@@ -3,21 +3,28 @@ package game
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
-	"math/rand"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/ardanlabs/ethereum/currency"
+	"github.com/ardanlabs/liarsdice/business/core/game/fair"
 	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ardanlabs/liarsdice/foundation/validate"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/google/uuid"
 )
 
-var ErrNotFound = errors.New("game not found")
+// Set of error variables for playing games.
+var (
+	ErrNotFound  = errors.New("game not found")
+	ErrNotSeated = errors.New("player not seated")
+)
 
 // Storer interface declares the behaviour this package needs to persist and
 // retrieve data.
@@ -25,6 +32,12 @@ type Storer interface {
 	Create(ctx context.Context, g *Game) error
 	InsertRound(ctx context.Context, state State) error
 	QueryStateByID(ctx context.Context, gameID uuid.UUID, round int) (State, error)
+	InsertEvent(ctx context.Context, e Event) error
+	QueryEvents(ctx context.Context, gameID uuid.UUID) ([]Event, error)
+	QueryServerSeeds(ctx context.Context, gameID uuid.UUID) ([]ServerSeeds, error)
+	QueryUnreconciled(ctx context.Context) ([]uuid.UUID, error)
+	QueryGames(ctx context.Context, filter QueryFilter, after Cursor, limit int) ([]Summary, error)
+	QueryRounds(ctx context.Context, gameID uuid.UUID) ([]State, error)
 }
 
 // Banker represents the ability to manage money for the game. Deposits and
@@ -34,39 +47,77 @@ type Banker interface {
 	Reconcile(ctx context.Context, winningPlayer common.Address, losingPlayers []common.Address, anteGWei *big.Float, gameFeeGWei *big.Float) (*types.Transaction, *types.Receipt, error)
 }
 
+// Accounter represents a banker that backs some seats with an account other
+// than the player's, like the engine's account backing the seats of bots.
+// The balance of that account is checked and reserved for the seats and the
+// seats are settled against it.
+type Accounter interface {
+	Account(gameID uuid.UUID, player common.Address) common.Address
+}
+
+// Rater represents the ability to rate the players of a game once the game
+// has been reconciled.
+type Rater interface {
+	Rate(ctx context.Context, state State) error
+}
+
 // Game represents a single game that is being played.
 type Game struct {
 	log             *logger.Logger
 	converter       *currency.Converter
 	storer          Storer
 	banker          Banker
+	dicer           Dicer
 	mu              sync.RWMutex
-	id              uuid.UUID              // Unique game id.
-	dateCreated     time.Time              // The time the game was created. Used to help with caching.
-	round           int                    // Current round of the game.
-	status          string                 // Current status of the game.
-	anteUSD         float64                // The ante for joining this game.
-	playerLastOut   common.Address         // The player who lost the last round.
-	playerLastWin   common.Address         // The player who won the last round.
-	playerTurn      int                    // The index of the player who's turn it is.
-	players         []common.Address       // Game players in the order they were added.
-	existingPlayers []common.Address       // The set of players still in the game.
-	cups            map[common.Address]Cup // Game players with indexes cup access.
-	bets            []Bet                  // History of bets for the current round.
-	balancesGWei    []Balance              // The balances of the players when added.
+	id              uuid.UUID                 // Unique game id.
+	dateCreated     time.Time                 // The time the game was created. Used to help with caching.
+	round           int                       // Current round of the game.
+	status          string                    // Current status of the game.
+	anteUSD         float64                   // The ante for joining this game.
+	rules           Rules                     // The rule variants the game is played with.
+	palifico        bool                      // The current round is played without wilds.
+	turnDeadline    time.Time                 // The time the current player's turn expires.
+	playerLastOut   common.Address            // The player who lost the last round.
+	playerLastWin   common.Address            // The player who won the last round.
+	playerTurn      int                       // The index of the player who's turn it is.
+	players         []common.Address          // Game players in the order they were added.
+	existingPlayers []common.Address          // The set of players still in the game.
+	cups            map[common.Address]Cup    // Game players with indexes cup access.
+	bets            []Bet                     // History of bets for the current round.
+	balancesGWei    []Balance                 // The balances of the players when added.
+	serverSeed      string                    // Secret seed used to derive the dice for the current round.
+	nextServerSeed  string                    // Secret seed committed for the next round.
+	clientSeeds     map[common.Address]string // Seeds provided by players to mix into their rolls.
+	proofs          []Proof                   // Revealed seeds and dice for the rounds that are over.
+	reveal          Reveal                    // The cups and outcome of the last round that ended.
+	events          []Event                   // Ordered log of the changes made to the game.
+	rematch         map[common.Address]bool   // The players' answers to a rematch once the game is reconciled.
+	rematchID       uuid.UUID                 // The game that was started as the rematch.
+	rematchDeadline time.Time                 // The time the players have to answer a rematch.
 }
 
 // New creates a new game.
-func New(ctx context.Context, log *logger.Logger, converter *currency.Converter, storer Storer, banker Banker, player common.Address, anteUSD float64) (*Game, error) {
+func New(ctx context.Context, log *logger.Logger, converter *currency.Converter, storer Storer, banker Banker, dicer Dicer, player common.Address, anteUSD float64, rules Rules) (*Game, error) {
+	if anteUSD <= 0 {
+		return nil, fmt.Errorf("ante must be greater than zero: ante[%v]", anteUSD)
+	}
+
+	rules = rules.withSeats()
+	if err := rules.validate(); err != nil {
+		return nil, err
+	}
+
 	balance, err := banker.AccountBalance(ctx, player)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve account[%s] balance", player)
 	}
 
-	// If comparison is negative, the player has no balance.
+	// If comparison is negative, the player has no balance that isn't
+	// already reserved for other games. The buy-in for a tournament game
+	// is reserved by the tournament.
 	anteGWei := converter.USD2GWei(big.NewFloat(anteUSD))
-	if balance.Cmp(anteGWei) < 0 {
-		return nil, fmt.Errorf("account [%s] does not have enough balance to play, balance[%v]", player, balance)
+	if available := Escrow.Available(player, balance); !rules.Tournament() && available.Cmp(anteGWei) < 0 {
+		return nil, fmt.Errorf("account [%s] does not have enough available balance to play, available[%v]", player, available)
 	}
 
 	g := Game{
@@ -74,19 +125,44 @@ func New(ctx context.Context, log *logger.Logger, converter *currency.Converter,
 		converter:   converter,
 		storer:      storer,
 		banker:      banker,
+		dicer:       dicer,
 		id:          uuid.New(),
 		status:      StatusNewGame,
 		round:       0,
 		anteUSD:     anteUSD,
+		rules:       rules,
 		cups:        make(map[common.Address]Cup),
+		clientSeeds: make(map[common.Address]string),
 		dateCreated: time.Now().UTC(),
 	}
 
-	if err := g.AddAccount(ctx, player); err != nil {
+	// Commit to the seeds for the first two rounds. The seed for a round is
+	// always committed a round early so players can change their client
+	// seed knowing the server seed is already fixed.
+	g.serverSeed = g.newServerSeed()
+	g.nextServerSeed = g.newServerSeed()
+
+	g.addEvent(Event{
+		Type:               EventNew,
+		Player:             player,
+		Date:               g.dateCreated,
+		AnteUSD:            anteUSD,
+		Rules:              rules,
+		ServerSeed:         g.serverSeed,
+		ServerSeedHash:     fair.Hash(g.serverSeed),
+		NextServerSeedHash: fair.Hash(g.nextServerSeed),
+		NextServerSeed:     g.nextServerSeed,
+	})
+
+	// The events for a new game are stored when the game is created.
+	e, err := g.addAccount(ctx, player)
+	if err != nil {
 		return nil, errors.New("unable to add owner to the game")
 	}
+	g.addEvent(e)
 
 	if err := g.storer.Create(ctx, &g); err != nil {
+		Escrow.ReleaseAll(g.id)
 		return nil, errors.New("unable to add the game to the db")
 	}
 
@@ -97,6 +173,147 @@ func New(ctx context.Context, log *logger.Logger, converter *currency.Converter,
 	return &g, nil
 }
 
+// Load rebuilds a game from the events in the store so a game can be resumed
+// after the engine restarts.
+func Load(ctx context.Context, log *logger.Logger, converter *currency.Converter, storer Storer, banker Banker, dicer Dicer, gameID uuid.UUID) (*Game, error) {
+	events, err := storer.QueryEvents(ctx, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+
+	// The events are stored without the secret seeds.
+	seeds, err := storer.QueryServerSeeds(ctx, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("query server seeds: %w", err)
+	}
+
+	bySequence := make(map[int]ServerSeeds, len(seeds))
+	for _, s := range seeds {
+		bySequence[s.Sequence] = s
+	}
+
+	for i, e := range events {
+		if s, exists := bySequence[e.Sequence]; exists {
+			if e.Type == EventNew {
+				events[i].ServerSeed = s.ServerSeed
+			}
+			events[i].NextServerSeed = s.NextServerSeed
+		}
+	}
+
+	g := Game{
+		log:         log,
+		converter:   converter,
+		storer:      storer,
+		banker:      banker,
+		dicer:       dicer,
+		id:          gameID,
+		clientSeeds: make(map[common.Address]string),
+		events:      events,
+	}
+
+	// The secret seeds, client seeds and proofs are not part of the state,
+	// so they are captured while the events are replayed.
+	state, err := replay(events, func(state *State, e Event) {
+		switch e.Type {
+		case EventNew:
+			g.anteUSD = e.AnteUSD
+			g.serverSeed = e.ServerSeed
+			g.nextServerSeed = e.NextServerSeed
+
+		case EventRoll:
+			g.clientSeeds[e.Player] = e.ClientSeed
+
+		case EventLiar, EventExact, EventForfeit:
+			g.proofs = append(g.proofs, newProof(state.Round, e.ServerSeed, state.ExistingPlayers, state.Cups))
+
+		case EventNextRound:
+			if e.Status != StatusGameOver {
+				g.serverSeed = g.nextServerSeed
+				g.nextServerSeed = e.NextServerSeed
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+
+	g.dateCreated = state.DateCreated
+	g.round = state.Round
+	g.status = state.Status
+	g.rules = state.Rules
+	g.palifico = state.Palifico
+	g.playerLastOut = state.PlayerLastOut
+	g.playerLastWin = state.PlayerLastWin
+	g.reveal = state.Reveal
+	g.cups = state.Cups
+	g.bets = state.Bets
+	g.players = state.ExistingPlayers
+	g.playerTurn = state.Cups[state.PlayerTurn].OrderIdx
+
+	// Players who reached the max outs are no longer in the game.
+	g.existingPlayers = make([]common.Address, len(g.players))
+	for i, player := range g.players {
+		if g.cups[player].Outs < g.rules.MaxOuts() {
+			g.existingPlayers[i] = player
+		}
+	}
+
+	// The antes for the seats are held until the game is reconciled.
+	switch {
+	case g.status == StatusReconciled, g.status == StatusAbandoned, g.rules.Tournament():
+	default:
+		anteGWei := converter.USD2GWei(big.NewFloat(g.anteUSD))
+
+		// An account backing many seats holds the ante for each of them.
+		held := make(map[common.Address]*big.Float)
+		for _, player := range g.players {
+			account := g.account(player)
+			if _, exists := held[account]; !exists {
+				held[account] = new(big.Float)
+			}
+			held[account].Add(held[account], anteGWei)
+		}
+
+		Escrow.mu.Lock()
+		for account, amount := range held {
+			Escrow.hold(g.id, account, amount)
+		}
+		Escrow.mu.Unlock()
+	}
+
+	// The balances are only stored in USD, so use the bank to get the
+	// precise balance for each player.
+	for _, balance := range state.Balances {
+		balanceGWei, err := banker.AccountBalance(ctx, g.account(balance.Player))
+		if err != nil {
+			usd, _ := strconv.ParseFloat(balance.Amount, 64)
+			balanceGWei = converter.USD2GWei(big.NewFloat(usd))
+		}
+
+		g.balancesGWei = append(g.balancesGWei, Balance{
+			Player: balance.Player,
+			Amount: balanceGWei,
+		})
+	}
+
+	// Players get a full turn once the game is resumed.
+	g.resetTurnDeadline()
+
+	log.Info(ctx, "game.load", "id", g.id, "status", g.status, "round", g.round, "events", len(events))
+
+	return &g, nil
+}
+
+// account returns the account in the contract that backs the player's seat.
+func (g *Game) account(player common.Address) common.Address {
+	if a, ok := g.banker.(Accounter); ok {
+		return a.Account(g.id, player)
+	}
+
+	return player
+}
+
 // ID returns the game id.
 func (g *Game) ID() uuid.UUID {
 	return g.id
@@ -115,46 +332,73 @@ func (g *Game) Status() string {
 	return g.status
 }
 
+// Private reports if the game is hidden from the lobby.
+func (g *Game) Private() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.rules.Private
+}
+
 // AddAccount adds a player to the game. If the account already exists, the
 // function will return an error.
 func (g *Game) AddAccount(ctx context.Context, player common.Address) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	e, err := g.addAccount(ctx, player)
+	if err != nil {
+		return err
+	}
+
+	g.record(ctx, e)
+
+	return nil
+}
+
+// addAccount adds a player to the game and returns the event describing the
+// change so the caller can decide when to store it.
+func (g *Game) addAccount(ctx context.Context, player common.Address) (Event, error) {
 	var empty common.Address
 	if player == empty {
-		return errors.New("account id provided is empty")
+		return Event{}, errors.New("account id provided is empty")
 	}
 
 	if _, exists := g.cups[player]; exists {
-		return fmt.Errorf("account id [%s] is already in the game", player)
+		return Event{}, fmt.Errorf("account id [%s] is already in the game", player)
 	}
 
 	if g.status != StatusNewGame {
-		return fmt.Errorf("game status is required to be over: status[%s]", g.status)
+		return Event{}, fmt.Errorf("game status is required to be over: status[%s]", g.status)
 	}
 
-	if _, exists := g.cups[player]; exists {
-		return fmt.Errorf("account id [%s] is already in the game", player)
+	if len(g.players) >= g.rules.MaxPlayers {
+		return Event{}, fmt.Errorf("max players sitting: max[%d]", g.rules.MaxPlayers)
 	}
 
-	balanceGwei, err := g.banker.AccountBalance(ctx, player)
+	account := g.account(player)
+
+	balanceGwei, err := g.banker.AccountBalance(ctx, account)
 	if err != nil {
-		return fmt.Errorf("unable to retrieve account id [%s] balance", player)
+		return Event{}, fmt.Errorf("unable to retrieve account id [%s] balance", account)
 	}
 
 	anteGWei := g.converter.USD2GWei(big.NewFloat(g.anteUSD))
 
-	// If comparison is negative, the player has no balance.
-	if balanceGwei.Cmp(anteGWei) < 0 {
-		return fmt.Errorf("player [%s] does not have enough balance to play", player)
+	// Reserve the ante for this seat from the balance of the account backing
+	// it that isn't already reserved for other seats. The buy-in for a
+	// tournament game is reserved by the tournament.
+	if !g.rules.Tournament() {
+		if err := Escrow.Reserve(g.id, account, balanceGwei, anteGWei); err != nil {
+			return Event{}, err
+		}
 	}
 
 	g.cups[player] = Cup{
 		OrderIdx: len(g.players),
 		Player:   player,
 		Outs:     0,
-		Dice:     make([]int, 5),
+		Dice:     make([]int, numberOfDice),
 	}
 
 	g.players = append(g.players, player)
@@ -165,9 +409,153 @@ func (g *Game) AddAccount(ctx context.Context, player common.Address) error {
 		Amount: balanceGwei,
 	})
 
+	e := Event{
+		Type:   EventJoin,
+		Player: player,
+		Balances: []BalanceFmt{
+			{
+				Player: player,
+				Amount: g.converter.GWei2USD(balanceGwei),
+			},
+		},
+	}
+
+	return e, nil
+}
+
+// Leave removes a player from the game. Before the game starts, the player's
+// seat is given up and the ante reserved for it is released. Once the game
+// has started, leaving forfeits the game: the player is given the max outs
+// and if only one player is left, that player wins the game.
+func (g *Game) Leave(ctx context.Context, player common.Address) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var empty common.Address
+	cup, exists := g.cups[player]
+	if player == empty || !exists {
+		return fmt.Errorf("%w: player[%s]", ErrNotSeated, player)
+	}
+
+	switch g.status {
+	case StatusNewGame:
+		g.leaveSeat(player)
+
+		g.record(ctx, Event{
+			Type:   EventLeave,
+			Player: player,
+		})
+
+	case StatusPlaying, StatusRoundOver:
+		if cup.Outs == g.rules.MaxOuts() {
+			return fmt.Errorf("player [%s] is already out of the game", player)
+		}
+
+		g.forfeitGame(player)
+
+		g.record(ctx, Event{
+			Type:   EventLeave,
+			Player: player,
+			Status: g.status,
+			Turn:   g.currentPlayer(),
+			Winner: g.playerLastWin,
+		})
+
+	default:
+		return fmt.Errorf("game status is required to not be over: status[%s]", g.status)
+	}
+
+	g.log.Info(ctx, "game.leave", "id", g.id, "player", player, "status", g.status)
+
 	return nil
 }
 
+// leaveSeat removes the player from a game that hasn't started and releases
+// the ante reserved for the seat.
+func (g *Game) leaveSeat(player common.Address) {
+	delete(g.cups, player)
+	delete(g.clientSeeds, player)
+
+	g.players = removePlayer(g.players, player)
+	g.existingPlayers = removePlayer(g.existingPlayers, player)
+
+	for i, player := range g.players {
+		cup := g.cups[player]
+		cup.OrderIdx = i
+		g.cups[player] = cup
+	}
+
+	balances := make([]Balance, 0, len(g.balancesGWei))
+	for _, balance := range g.balancesGWei {
+		if balance.Player != player {
+			balances = append(balances, balance)
+		}
+	}
+	g.balancesGWei = balances
+
+	Escrow.Release(g.id, g.account(player), g.converter.USD2GWei(big.NewFloat(g.anteUSD)))
+}
+
+// forfeitGame gives the player the max outs so they are out of the game. The
+// ante for the seat stays reserved since it's lost when the game reconciles.
+// The player's bets are taken back, so a player who is out can't be called.
+func (g *Game) forfeitGame(player common.Address) {
+	cup := g.cups[player]
+	cup.Outs = g.rules.MaxOuts()
+	cup.Dice = make([]int, g.rules.dice(cup.Outs))
+	g.cups[player] = cup
+
+	g.bets = removeBets(g.bets, player)
+
+	var empty common.Address
+	g.existingPlayers[cup.OrderIdx] = empty
+
+	var activePlayers []common.Address
+	for _, v := range g.existingPlayers {
+		if v != empty {
+			activePlayers = append(activePlayers, v)
+		}
+	}
+
+	// The last player left at the table wins the game.
+	if len(activePlayers) == 1 {
+		g.bets = []Bet{}
+		g.status = StatusGameOver
+		g.playerLastWin = activePlayers[0]
+		g.playerTurn = g.cups[activePlayers[0]].OrderIdx
+		return
+	}
+
+	// The turn moves on if the player was up.
+	if g.status == StatusPlaying && g.playerTurn == cup.OrderIdx {
+		g.nextTurn()
+	}
+}
+
+// removeBets returns the bets without the bets made by the specified player.
+func removeBets(bets []Bet, player common.Address) []Bet {
+	list := make([]Bet, 0, len(bets))
+	for _, bet := range bets {
+		if bet.Player != player {
+			list = append(list, bet)
+		}
+	}
+
+	return list
+}
+
+// removePlayer returns the players without the specified player.
+func removePlayer(players []common.Address, player common.Address) []common.Address {
+	list := make([]common.Address, 0, len(players))
+	for _, p := range players {
+		if p != player {
+			list = append(list, p)
+		}
+	}
+
+	return list
+}
+
 // StartGame changes the status to Playing to allow the game to begin.
 func (g *Game) StartGame(ctx context.Context) error {
 	g.mu.Lock()
@@ -177,13 +565,19 @@ func (g *Game) StartGame(ctx context.Context) error {
 		return fmt.Errorf("game status is required to be over: status[%s]", g.status)
 	}
 
-	if len(g.cups) < minNumberPlayers {
-		return errors.New("not enough players to start the game")
+	if len(g.cups) < g.rules.MinPlayers {
+		return fmt.Errorf("not enough players to start the game: players[%d] min[%d]", len(g.cups), g.rules.MinPlayers)
 	}
 
-	g.playerTurn = rand.Intn(len(g.cups))
+	g.playerTurn = g.dicer.Roll(len(g.cups)) - 1
 	g.status = StatusPlaying
 	g.round = 1
+	g.resetTurnDeadline()
+
+	g.record(ctx, Event{
+		Type: EventStart,
+		Turn: g.currentPlayer(),
+	})
 
 	return nil
 }
@@ -205,7 +599,7 @@ func (g *Game) ApplyOut(ctx context.Context, player common.Address, outs int) er
 		return fmt.Errorf("player [%s] does not exist in the game", player)
 	}
 
-	if outs < 0 || outs > 3 {
+	if outs < 0 || outs > g.rules.MaxOuts() {
 		return errors.New("invalid out value")
 	}
 
@@ -214,11 +608,12 @@ func (g *Game) ApplyOut(ctx context.Context, player common.Address, outs int) er
 	}
 
 	cup.Outs = outs
+	cup.Dice = make([]int, g.rules.dice(outs))
 	g.cups[player] = cup
 
-	// After 3 outs, an account is out of the game.
+	// After the max outs, an account is out of the game.
 	// We need to check if there is only 1 account left, end the round.
-	if outs == 3 {
+	if outs == g.rules.MaxOuts() {
 		var empty common.Address
 		g.existingPlayers[cup.OrderIdx] = empty
 
@@ -236,11 +631,19 @@ func (g *Game) ApplyOut(ctx context.Context, player common.Address, outs int) er
 		}
 	}
 
+	g.record(ctx, Event{
+		Type:   EventOut,
+		Player: player,
+		Outs:   outs,
+	})
+
 	return nil
 }
 
-// RollDice will generate 5 new random integers for the players cup. The caller
-// can specific the dice if they choose.
+// RollDice will generate new dice for the players cup. The caller can specific
+// the dice if they choose. A player can only roll once a round, so the dice
+// that were committed to can't be rolled again. Dice specified by the caller
+// replace the roll, which lets the tests mock the dice.
 func (g *Game) RollDice(ctx context.Context, player common.Address, manualRole ...int) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
@@ -254,28 +657,81 @@ func (g *Game) RollDice(ctx context.Context, player common.Address, manualRole .
 		return fmt.Errorf("game status is required to be playing: status[%s]", g.status)
 	}
 
+	if cup, exists := g.cups[player]; exists && cup.Commitment != "" && manualRole == nil {
+		return fmt.Errorf("player [%s] already rolled for round %d", player, g.round)
+	}
+
 	return g.rollDice(ctx, player, manualRole...)
 }
 
-// rollDice will generate 5 new random integers for the players cup. The caller
-// can specific the dice if they choose.
+// rollDice will generate new dice for the players cup. The caller can specific
+// the dice if they choose.
 func (g *Game) rollDice(ctx context.Context, player common.Address, manualRole ...int) error {
 	cup, exists := g.cups[player]
 	if !exists {
 		return fmt.Errorf("player [%s] does not exist in the game", player)
 	}
 
-	if manualRole == nil || len(manualRole) < 5 {
-		for i := range cup.Dice {
-			cup.Dice[i] = rand.Intn(6) + 1
-		}
+	clientSeed := g.clientSeeds[player]
+
+	if manualRole == nil || len(manualRole) < len(cup.Dice) {
+		nonce := fair.Nonce(g.id, g.round, player)
+		copy(cup.Dice, fair.Roll(g.serverSeed, clientSeed, nonce, len(cup.Dice)))
 	} else {
 		for i := range cup.Dice {
 			cup.Dice[i] = manualRole[i]
 		}
 	}
 
-	g.log.Info(ctx, "game.rolldice", "id", g.id, "player", player, "dice", cup.Dice)
+	// Capture the client seed used for this roll and publish a commitment
+	// of the dice so they can be verified once the round is over.
+	cup.ClientSeed = clientSeed
+	cup.Commitment = fair.Commit(g.serverSeed, player, cup.Dice)
+	g.cups[player] = cup
+
+	g.log.Info(ctx, "game.rolldice", "id", g.id, "player", player, "dice", cup.Dice, "commitment", cup.Commitment)
+
+	dice := make([]int, len(cup.Dice))
+	copy(dice, cup.Dice)
+
+	g.record(ctx, Event{
+		Type:       EventRoll,
+		Player:     player,
+		Dice:       dice,
+		ClientSeed: cup.ClientSeed,
+		Commitment: cup.Commitment,
+	})
+
+	return nil
+}
+
+// SetClientSeed sets the seed the player wants mixed into their next roll.
+// The server seed for that roll has already been committed, so the seed
+// can't be used by the engine to influence the dice. Once the player has
+// rolled for the round, the seed is used for the roll of the next round.
+func (g *Game) SetClientSeed(ctx context.Context, player common.Address, seed string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var empty common.Address
+	if player == empty {
+		return errors.New("player provided is empty")
+	}
+
+	if _, exists := g.cups[player]; !exists {
+		return fmt.Errorf("player [%s] does not exist in the game", player)
+	}
+
+	if len(seed) > maxClientSeedLength {
+		return fmt.Errorf("client seed can't be longer than %d characters", maxClientSeedLength)
+	}
+
+	switch g.status {
+	case StatusGameOver, StatusReconciled:
+		return fmt.Errorf("game status is required to not be over: status[%s]", g.status)
+	}
+
+	g.clientSeeds[player] = seed
 
 	return nil
 }
@@ -308,17 +764,10 @@ func (g *Game) Bet(ctx context.Context, player common.Address, number int, suit
 		return fmt.Errorf("player [%s] can't make a bet now", player)
 	}
 
-	// If this is not the first bet, we need to validate the bet is valid.
-	if len(g.bets) > 0 {
-		lastBet := g.bets[len(g.bets)-1]
-
-		if number < lastBet.Number {
-			return fmt.Errorf("bet number must be greater or equal to the last bet number: number[%d] last[%d]", number, lastBet.Number)
-		}
-
-		if number == lastBet.Number && suit <= lastBet.Suit {
-			return fmt.Errorf("bet suit must be greater than the last bet suit: suit[%d] last[%d]", suit, lastBet.Suit)
-		}
+	// The bet must be possible with the dice that are still in play and
+	// follow the bets already made based on the rules.
+	if err := g.rules.checkBet(g.bets, number, suit, g.diceInPlay(), g.palifico); err != nil {
+		return err
 	}
 
 	// Add the bet to the list.
@@ -335,9 +784,70 @@ func (g *Game) Bet(ctx context.Context, player common.Address, number int, suit
 	// Move the turn to the next player.
 	g.nextTurn()
 
+	g.record(ctx, Event{
+		Type:   EventBet,
+		Player: player,
+		Number: number,
+		Suit:   suit,
+		Turn:   g.currentPlayer(),
+	})
+
 	return nil
 }
 
+// checkBet validates the number and suit of a bet can be made with the dice
+// in play and can follow the bets already made in the round. The problems are
+// returned as field errors so they can be shown against the fields of the bet.
+func (r Rules) checkBet(bets []Bet, number int, suit int, inPlay int, palifico bool) error {
+	var fields validate.FieldErrors
+
+	switch {
+	case number < 1:
+		fields = append(fields, validate.FieldError{
+			Field: "number",
+			Err:   fmt.Sprintf("number must be 1 or greater: number[%d]", number),
+		})
+
+	case number > inPlay:
+		fields = append(fields, validate.FieldError{
+			Field: "number",
+			Err:   fmt.Sprintf("number can't be greater than the dice in play: number[%d] inplay[%d]", number, inPlay),
+		})
+	}
+
+	if suit < 1 || suit > NumberOfSuits {
+		fields = append(fields, validate.FieldError{
+			Field: "suit",
+			Err:   fmt.Sprintf("suit must be between 1 and %d: suit[%d]", NumberOfSuits, suit),
+		})
+	}
+
+	if len(fields) > 0 {
+		return fields
+	}
+
+	if err := r.validateBet(bets, number, suit, palifico); err != nil {
+		return validate.FieldErrors{{Field: "bet", Err: err.Error()}}
+	}
+
+	return nil
+}
+
+// diceInPlay returns the total number of dice held by the players who are
+// still in the game.
+func (g *Game) diceInPlay() int {
+	var empty common.Address
+
+	var total int
+	for _, player := range g.existingPlayers {
+		if player != empty {
+			total += len(g.cups[player].Dice)
+		}
+	}
+
+	return total
+}
+
 // NextTurn determines which account makes the next move.
 func (g *Game) NextTurn(ctx context.Context) error {
 	g.mu.Lock()
@@ -347,13 +857,145 @@ func (g *Game) NextTurn(ctx context.Context) error {
 		return fmt.Errorf("game status is required to be playing: status[%s]", g.status)
 	}
 
+	player := g.currentPlayer()
 	g.nextTurn()
 
+	g.record(ctx, Event{
+		Type:   EventTurn,
+		Player: player,
+		Turn:   g.currentPlayer(),
+	})
+
 	return nil
 }
 
+// ExpireTurn applies the rule's timeout action when the current player's turn
+// has expired. The player can be skipped, have the minimum bet made for
+// them or lose the round. If no bet is possible, the player loses the round.
+func (g *Game) ExpireTurn(ctx context.Context, now time.Time) (Timeout, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.status != StatusPlaying || g.rules.TurnTimeout == 0 || now.Before(g.turnDeadline) {
+		return Timeout{}, false
+	}
+
+	player := g.existingPlayers[g.playerTurn]
+
+	action := g.rules.TimeoutAction
+	switch action {
+	case TimeoutPass, TimeoutBid, TimeoutOut:
+	default:
+		action = TimeoutOut
+	}
+
+	switch action {
+	case TimeoutPass:
+		g.nextTurn()
+
+		g.record(ctx, Event{
+			Type:    EventTurn,
+			Player:  player,
+			Timeout: true,
+			Turn:    g.currentPlayer(),
+		})
+
+	case TimeoutBid:
+		number, suit, ok := g.rules.minimumBet(g.bets, g.diceInPlay(), g.palifico)
+		if !ok {
+			action = TimeoutOut
+			g.forfeitRound(ctx, player)
+			break
+		}
+
+		g.bets = append(g.bets, Bet{
+			Player: player,
+			Number: number,
+			Suit:   suit,
+		})
+		g.nextTurn()
+
+		g.record(ctx, Event{
+			Type:    EventBet,
+			Player:  player,
+			Timeout: true,
+			Number:  number,
+			Suit:    suit,
+			Turn:    g.currentPlayer(),
+		})
+
+	case TimeoutOut:
+		g.forfeitRound(ctx, player)
+	}
+
+	g.log.Info(ctx, "game.expireturn", "id", g.id, "player", player, "action", action)
+
+	t := Timeout{
+		GameID: g.id,
+		Player: player,
+		Action: action,
+	}
+
+	return t, true
+}
+
+// forfeitRound ends the round with the specified player as the loser.
+func (g *Game) forfeitRound(ctx context.Context, player common.Address) {
+	g.status = StatusRoundOver
+
+	// Reveal the server seed and dice for this round.
+	g.proofs = append(g.proofs, g.proof())
+
+	// The winner is the player who made the last bet, or the next player
+	// if no bets were made.
+	g.playerLastOut = player
+	if len(g.bets) > 0 {
+		g.playerLastWin = g.bets[len(g.bets)-1].Player
+	} else {
+		g.nextTurn()
+		g.playerLastWin = g.existingPlayers[g.playerTurn]
+	}
+
+	g.addOut(player)
+
+	g.reveal = newReveal(g.round, g.players, g.cups, g.bets, g.rules, g.palifico, false, g.playerLastWin, g.playerLastOut)
+
+	g.record(ctx, Event{
+		Type:       EventForfeit,
+		Player:     player,
+		Timeout:    true,
+		Winner:     g.playerLastWin,
+		Loser:      g.playerLastOut,
+		Turn:       g.currentPlayer(),
+		ServerSeed: g.serverSeed,
+	})
+
+	if err := g.storer.InsertRound(ctx, g.state()); err != nil {
+		g.log.Error(ctx, "forfeit.store.insertRound", "id", g.id, "ERROR", err)
+	}
+}
+
+// addOut gives the player an out for losing a round. A player who is already
+// out of the game can't lose any more.
+func (g *Game) addOut(player common.Address) {
+	cup := g.cups[player]
+	if cup.Outs < g.rules.MaxOuts() {
+		cup.Outs++
+	}
+	g.cups[player] = cup
+}
+
+// resetTurnDeadline starts the clock for the current player's turn.
+func (g *Game) resetTurnDeadline() {
+	if g.rules.TurnTimeout > 0 {
+		g.turnDeadline = time.Now().Add(g.rules.TurnTimeout)
+	}
+}
+
 // nextTurn determines which account makes the next move.
 func (g *Game) nextTurn() {
+	defer g.resetTurnDeadline()
+
 	l := len(g.existingPlayers)
 
 	for i := 0; i < l; i++ {
@@ -379,6 +1021,28 @@ func (g *Game) CallLiar(ctx context.Context, player common.Address) (winningPlay
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	return g.callBet(ctx, player, false)
+}
+
+// CallExact claims the last bet that was made is exactly right and
+// determines the winner and loser of the current round. This call is only
+// available when the spot on rule is being used.
+func (g *Game) CallExact(ctx context.Context, player common.Address) (winningPlayer common.Address, losingPlayer common.Address, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.rules.SpotOn {
+		var empty common.Address
+		return empty, empty, fmt.Errorf("spot on calls are not allowed with the %s ruleset", g.rules.Ruleset)
+	}
+
+	return g.callBet(ctx, player, true)
+}
+
+// callBet ends the round by challenging the last bet. A liar call wins when
+// there are fewer dice than the bet claimed, an exact call wins when there
+// are exactly as many dice as the bet claimed.
+func (g *Game) callBet(ctx context.Context, player common.Address, exact bool) (winningPlayer common.Address, losingPlayer common.Address, err error) {
 	var empty common.Address
 
 	if player == empty {
@@ -407,40 +1071,55 @@ func (g *Game) CallLiar(ctx context.Context, player common.Address) (winningPlay
 	// This call ends the round, not allowing any more bets to be made.
 	g.status = StatusRoundOver
 
-	// Hold the sum of all the dice values.
-	dice := make([]int, 7)
-	for _, player := range g.cups {
-		for _, suit := range player.Dice {
-			dice[suit]++
-		}
-	}
-
 	// Capture the last bet that was made.
 	lastBet := g.bets[len(g.bets)-1]
 
+	// Count the dice that match the suit of the last bet.
+	total := g.rules.count(g.cups, lastBet.Suit, g.palifico)
+
+	// Reveal the server seed and dice for this round.
+	g.proofs = append(g.proofs, g.proof())
+
+	callerWon := total < lastBet.Number
+	if exact {
+		callerWon = total == lastBet.Number
+	}
+
 	// Identify the winner and the loser.
 	switch {
-	case dice[lastBet.Suit] < lastBet.Number:
+	case callerWon:
 
 		// The account who made the last bet lost.
-		cup := g.cups[lastBet.Player]
-		cup.Outs++
-		g.cups[lastBet.Player] = cup
+		g.addOut(lastBet.Player)
 
-		g.playerLastOut = cup.Player
+		g.playerLastOut = lastBet.Player
 		g.playerLastWin = player
 
 	default:
 
-		// The account who called liar lost.
-		cup := g.cups[player]
-		cup.Outs++
-		g.cups[player] = cup
+		// The account who made the call lost.
+		g.addOut(player)
 
 		g.playerLastOut = player
 		g.playerLastWin = lastBet.Player
 	}
 
+	typ := EventLiar
+	if exact {
+		typ = EventExact
+	}
+
+	g.record(ctx, Event{
+		Type:       typ,
+		Player:     player,
+		Winner:     g.playerLastWin,
+		Loser:      g.playerLastOut,
+		Turn:       g.currentPlayer(),
+		ServerSeed: g.serverSeed,
+	})
+
+	g.reveal = newReveal(g.round, g.players, g.cups, g.bets, g.rules, g.palifico, exact, g.playerLastWin, g.playerLastOut)
+
 	// Not sure I want to return an error if I can't save this round
 	// to the database. It just means we can't recover this game properly.
 	// Since nothing happens to the bank, no one is losing money nor is
@@ -463,49 +1142,79 @@ func (g *Game) NextRound(ctx context.Context) (int, error) {
 		return 0, errors.New("current round is not over")
 	}
 
-	// If an account has three outs, remove their account from game play.
+	// If an account has the max outs, remove their account from game play.
 	// Reset the last bet value and dice.
 	var leftToPlay int
+	var empty common.Address
 	for player, cup := range g.cups {
-		cup.Dice = make([]int, 5)
+		cup.Dice = make([]int, g.rules.dice(cup.Outs))
+		cup.ClientSeed = ""
+		cup.Commitment = ""
 		g.cups[player] = cup
 
-		for i := range cup.Dice {
-			cup.Dice[i] = 0
-		}
-
-		if cup.Outs == 3 {
-			var empty common.Address
+		if cup.Outs == g.rules.MaxOuts() {
 			g.existingPlayers[cup.OrderIdx] = empty
 			continue
 		}
 
-		g.rollDice(ctx, player)
-
 		leftToPlay++
 	}
 
 	// If there is only 1 player left we have a winner.
-	// Reset the bets, the dice, and status.
+	// Reset the bets and status.
 	if leftToPlay == 1 {
 		g.bets = []Bet{}
 		g.status = StatusGameOver
-		g.rollDice(ctx, g.playerLastWin, 0, 0, 0, 0, 0)
+
+		g.record(ctx, Event{
+			Type:   EventNextRound,
+			Status: g.status,
+		})
+
 		return 1, nil
 	}
 
 	// Figure out who starts the next round.
 	// The person who was last out should start the round unless they are out.
-	if g.cups[g.playerLastOut].Outs != 3 {
+	if g.cups[g.playerLastOut].Outs != g.rules.MaxOuts() {
 		g.playerTurn = g.cups[g.playerLastOut].OrderIdx
 	} else {
 		g.playerTurn = g.cups[g.playerLastWin].OrderIdx
 	}
 
-	// Reset the game state.
+	// The player who was picked could have left the game after the round.
+	if g.existingPlayers[g.playerTurn] == empty {
+		g.nextTurn()
+	}
+
+	// A player who just reached their last out starts a palifico round.
+	g.palifico = g.rules.Palifico && g.cups[g.playerLastOut].Outs == g.rules.MaxOuts()-1
+
+	// Reset the game state and move to the server seed that was committed
+	// during the last round.
 	g.bets = []Bet{}
 	g.status = StatusPlaying
 	g.round++
+	g.serverSeed = g.nextServerSeed
+	g.nextServerSeed = g.newServerSeed()
+	g.resetTurnDeadline()
+
+	g.record(ctx, Event{
+		Type:               EventNextRound,
+		Status:             g.status,
+		Turn:               g.currentPlayer(),
+		Palifico:           g.palifico,
+		ServerSeedHash:     fair.Hash(g.serverSeed),
+		NextServerSeedHash: fair.Hash(g.nextServerSeed),
+		NextServerSeed:     g.nextServerSeed,
+	})
+
+	// Roll the dice for the players still in the game.
+	for _, player := range g.existingPlayers {
+		if player != empty {
+			g.rollDice(ctx, player)
+		}
+	}
 
 	// Return the number of players for this round.
 	return leftToPlay, nil
@@ -513,18 +1222,34 @@ func (g *Game) NextRound(ctx context.Context) (int, error) {
 
 // Reconcile calculates the game pot and make the transfer to the winner.
 func (g *Game) Reconcile(ctx context.Context) (*types.Transaction, *types.Receipt, error) {
+	tx, receipt, state, err := g.reconcile(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The players are rated without holding the game lock, since rating
+	// takes the tables lock and writes to the database.
+	Tables.rate(ctx, g.log, state)
+
+	return tx, receipt, nil
+}
+
+// reconcile settles the game with the bank and returns the state of the
+// reconciled game.
+func (g *Game) reconcile(ctx context.Context) (*types.Transaction, *types.Receipt, State, error) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
 	if g.status != StatusGameOver {
-		return nil, nil, fmt.Errorf("game status is required to be gameover: status[%s]", g.status)
+		return nil, nil, State{}, fmt.Errorf("game status is required to be gameover: status[%s]", g.status)
 	}
 
-	// Find the losers.
+	// Find the losers. The seats are settled against the accounts backing
+	// them.
 	var losingPlayers []common.Address
 	for _, cup := range g.cups {
 		if g.playerLastWin != cup.Player {
-			losingPlayers = append(losingPlayers, cup.Player)
+			losingPlayers = append(losingPlayers, g.account(cup.Player))
 		}
 	}
 
@@ -538,21 +1263,36 @@ func (g *Game) Reconcile(ctx context.Context) (*types.Transaction, *types.Receip
 		g.log.Info(ctx, "game.reconcole", "id", g.id, "loser", player)
 	}
 
-	// Perform the reconcile against the bank.
-	tx, receipt, err := g.banker.Reconcile(ctx, g.playerLastWin, losingPlayers, antiGWei, gameFeeGWei)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to reconcile the game: %w", err)
+	// Perform the reconcile against the bank. The funds for a tournament
+	// game are settled once the tournament is over, so no transaction is
+	// made for the game.
+	var tx *types.Transaction
+	var receipt *types.Receipt
+	if !g.rules.Tournament() {
+		var err error
+		tx, receipt, err = g.banker.Reconcile(ctx, g.account(g.playerLastWin), losingPlayers, antiGWei, gameFeeGWei)
+		if err != nil {
+			return nil, nil, State{}, fmt.Errorf("failed to reconcile the game: %w", err)
+		}
+
+		g.log.Info(ctx, "game.reconcole.contract", "id", g.id, "tx", g.converter.CalculateTransactionDetails(tx), "receipt", g.converter.CalculateReceiptDetails(receipt, tx.GasPrice()))
 	}
 
-	g.log.Info(ctx, "game.reconcole.contract", "id", g.id, "tx", g.converter.CalculateTransactionDetails(tx), "receipt", g.converter.CalculateReceiptDetails(receipt, tx.GasPrice()))
+	// The antes have been paid so the seats no longer need to be reserved.
+	Escrow.ReleaseAll(g.id)
 
 	g.status = StatusReconciled
 	g.round++
 
+	// The players who don't answer the rematch in a turn are declined.
+	if g.rules.TurnTimeout > 0 {
+		g.rematchDeadline = time.Now().Add(g.rules.TurnTimeout)
+	}
+
 	// Update the player balances.
 	g.log.Info(ctx, "game.reconcole.fees", "id", g.id, "anteUSD", g.anteUSD, "antiGWei", antiGWei, "gameFeeGWei", gameFeeGWei)
 	for i, player := range g.players {
-		balanceGwei, err := g.banker.AccountBalance(ctx, player)
+		balanceGwei, err := g.banker.AccountBalance(ctx, g.account(player))
 		if err != nil {
 			g.log.Info(ctx, "game.reconcole.updatebalance", "ERROR", err)
 			continue
@@ -568,11 +1308,69 @@ func (g *Game) Reconcile(ctx context.Context) (*types.Transaction, *types.Receip
 		g.log.Info(ctx, "game.reconcole.updatebalance", "id", g.id, "player", player, "oldBlanceGWei", oldBalanceGWei, "balanceGWei", balanceGwei)
 	}
 
-	if err := g.storer.InsertRound(ctx, g.state()); err != nil {
+	balances := make([]BalanceFmt, len(g.balancesGWei))
+	for i, balance := range g.balancesGWei {
+		balances[i] = BalanceFmt{
+			Player: balance.Player,
+			Amount: g.converter.GWei2USD(balance.Amount),
+		}
+	}
+
+	g.record(ctx, Event{
+		Type:     EventReconcile,
+		Player:   g.playerLastWin,
+		Balances: balances,
+	})
+
+	state := g.state()
+
+	if err := g.storer.InsertRound(ctx, state); err != nil {
 		g.log.Error(ctx, "reconcile.store.insertRound", "id", g.id, "ERROR", err)
 	}
 
-	return tx, receipt, nil
+	return tx, receipt, state, nil
+}
+
+// Abandon flags a game that is no longer being played so it's not resumed.
+// A game that is over can be abandoned when it can't be reconciled.
+func (g *Game) Abandon(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch g.status {
+	case StatusNewGame, StatusPlaying, StatusRoundOver, StatusGameOver:
+	default:
+		return fmt.Errorf("game status is required to not be settled: status[%s]", g.status)
+	}
+
+	g.status = StatusAbandoned
+
+	// No money changes hands for an abandoned game.
+	Escrow.ReleaseAll(g.id)
+
+	g.record(ctx, Event{
+		Type: EventAbandon,
+	})
+
+	g.log.Info(ctx, "game.abandon", "id", g.id, "round", g.round)
+
+	return nil
+}
+
+// activity returns the status of the game and the time of the last event
+// that was caused by a player. Events caused by expired turns are not player
+// activity.
+func (g *Game) activity() (string, time.Time) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for i := len(g.events) - 1; i >= 0; i-- {
+		if !g.events[i].Timeout {
+			return g.status, g.events[i].Date
+		}
+	}
+
+	return g.status, g.dateCreated
 }
 
 // State returns a copy of the game state.
@@ -603,25 +1401,179 @@ func (g *Game) state() State {
 		}
 	}
 
-	var playerTurn common.Address
-	if len(g.existingPlayers) > 0 {
-		playerTurn = g.existingPlayers[g.playerTurn]
+	playerTurn := g.currentPlayer()
+
+	// The server seed is only shared once the round is over.
+	var serverSeed string
+	if g.status == StatusRoundOver {
+		serverSeed = g.serverSeed
 	}
 
 	return State{
-		GameID:          g.id,
-		GameName:        g.id.String(),
-		DateCreated:     g.dateCreated,
-		Round:           g.round,
-		Status:          g.status,
-		PlayerLastOut:   g.playerLastOut,
-		PlayerLastWin:   g.playerLastWin,
-		PlayerTurn:      playerTurn,
-		ExistingPlayers: existingPlayers,
-		Cups:            cups,
-		Bets:            bets,
-		Balances:        balances,
+		GameID:             g.id,
+		Sequence:           len(g.events),
+		GameName:           g.id.String(),
+		DateCreated:        g.dateCreated,
+		AnteUSD:            g.anteUSD,
+		Round:              g.round,
+		Status:             g.status,
+		PlayerLastOut:      g.playerLastOut,
+		PlayerLastWin:      g.playerLastWin,
+		PlayerTurn:         playerTurn,
+		ExistingPlayers:    existingPlayers,
+		Cups:               cups,
+		Bets:               bets,
+		Balances:           balances,
+		Rules:              g.rules,
+		Palifico:           g.palifico,
+		Reveal:             g.reveal,
+		TurnDeadline:       g.turnDeadline,
+		ServerSeed:         serverSeed,
+		ServerSeedHash:     fair.Hash(g.serverSeed),
+		NextServerSeedHash: fair.Hash(g.nextServerSeed),
+	}
+}
+
+// currentPlayer returns the player whose turn it is.
+func (g *Game) currentPlayer() common.Address {
+	if len(g.existingPlayers) == 0 {
+		var empty common.Address
+		return empty
+	}
+
+	return g.existingPlayers[g.playerTurn]
+}
+
+// Events returns a copy of the events recorded for the game.
+func (g *Game) Events() []Event {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	events := make([]Event, len(g.events))
+	copy(events, g.events)
+
+	return events
+}
+
+// addEvent assigns the next sequence number to the event and adds it to the
+// game's event log.
+func (g *Game) addEvent(e Event) Event {
+	e.GameID = g.id
+	e.Sequence = len(g.events) + 1
+	e.Round = g.round
+	if e.Date.IsZero() {
+		e.Date = time.Now().UTC()
+	}
+
+	g.events = append(g.events, e)
+
+	return e
+}
+
+// record adds the event to the game's event log and stores it. Like the
+// rounds, a failure to store the event is logged and doesn't stop the game.
+func (g *Game) record(ctx context.Context, e Event) {
+	e = g.addEvent(e)
+
+	if err := g.storer.InsertEvent(ctx, e); err != nil {
+		g.log.Error(ctx, "game.store.insertEvent", "id", g.id, "sequence", e.Sequence, "ERROR", err)
+	}
+}
+
+// Proof returns the revealed server seed and dice for the specified round so
+// the roll can be verified. A round is only revealed once it's over.
+func (g *Game) Proof(round int) (Proof, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for _, proof := range g.proofs {
+		if proof.Round == round {
+			return proof, nil
+		}
+	}
+
+	return Proof{}, fmt.Errorf("round [%d] has not been revealed", round)
+}
+
+// proof captures the server seed and dice for the current round.
+func (g *Game) proof() Proof {
+	return newProof(g.round, g.serverSeed, g.players, g.cups)
+}
+
+// newProof captures the server seed and the dice of the players for a round.
+func newProof(round int, serverSeed string, players []common.Address, cupsByPlayer map[common.Address]Cup) Proof {
+	var cups []fair.Cup
+	for _, player := range players {
+		cup := cupsByPlayer[player]
+
+		// Players who are out of the game didn't roll this round.
+		if cup.Commitment == "" {
+			continue
+		}
+
+		dice := make([]int, len(cup.Dice))
+		copy(dice, cup.Dice)
+
+		cups = append(cups, fair.Cup{
+			Player:     player,
+			ClientSeed: cup.ClientSeed,
+			Dice:       dice,
+			Commitment: cup.Commitment,
+		})
 	}
+
+	return Proof{
+		Round:          round,
+		ServerSeed:     serverSeed,
+		ServerSeedHash: fair.Hash(serverSeed),
+		Cups:           cups,
+	}
+}
+
+// newReveal captures the cups of the players who rolled and the outcome of
+// the round that ended.
+func newReveal(round int, players []common.Address, cupsByPlayer map[common.Address]Cup, bets []Bet, rules Rules, palifico bool, exact bool, winner common.Address, loser common.Address) Reveal {
+	var cups []Cup
+	for _, player := range players {
+		cup := cupsByPlayer[player]
+
+		// Players who are out of the game didn't roll this round.
+		if cup.Commitment == "" {
+			continue
+		}
+
+		dice := make([]int, len(cup.Dice))
+		copy(dice, cup.Dice)
+		cup.Dice = dice
+
+		cups = append(cups, cup)
+	}
+
+	reveal := Reveal{
+		Round:  round,
+		Cups:   cups,
+		Exact:  exact,
+		Winner: winner,
+		Loser:  loser,
+	}
+
+	// A round can end without a bet when a player's turn expires.
+	if len(bets) > 0 {
+		reveal.Bet = bets[len(bets)-1]
+		reveal.Total = rules.count(cupsByPlayer, reveal.Bet.Suit, palifico)
+	}
+
+	return reveal
+}
+
+// newServerSeed generates a new secret server seed from the game's dicer.
+func (g *Game) newServerSeed() string {
+	seed := make([]byte, 32)
+	for i := range seed {
+		seed[i] = byte(g.dicer.Roll(256) - 1)
+	}
+
+	return hex.EncodeToString(seed)
 }
 
 // QueryState retrieves the state for the current round of this game.
@@ -2,6 +2,8 @@ package game_test
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"math/big"
 	"os"
@@ -13,10 +15,12 @@ import (
 	"github.com/ardanlabs/ethereum/currency"
 	scbank "github.com/ardanlabs/liarsdice/business/contract/go/bank"
 	"github.com/ardanlabs/liarsdice/business/core/bank"
+	"github.com/ardanlabs/liarsdice/business/core/bot"
 	"github.com/ardanlabs/liarsdice/business/core/game"
 	"github.com/ardanlabs/liarsdice/business/core/game/stores/gamedb"
 	"github.com/ardanlabs/liarsdice/business/data/dbtest"
 	"github.com/ardanlabs/liarsdice/foundation/docker"
+	"github.com/ardanlabs/liarsdice/foundation/validate"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -26,9 +30,12 @@ var (
 	ownerClt   *ethereum.Client
 	player1Clt *ethereum.Client
 	player2Clt *ethereum.Client
+	player3Clt *ethereum.Client
 )
 
 func TestMain(m *testing.M) {
+	flag.Parse()
+
 	code, err := run(m)
 	if err != nil {
 		fmt.Println(err)
@@ -40,13 +47,17 @@ func TestMain(m *testing.M) {
 func run(m *testing.M) (int, error) {
 	var err error
 
-	c, err = dbtest.StartDB()
-	if err != nil {
-		return 1, fmt.Errorf("starting database: %w", err)
+	// The tests that store games are skipped in short mode, so the database
+	// isn't needed.
+	if !testing.Short() {
+		c, err = dbtest.StartDB()
+		if err != nil {
+			return 1, fmt.Errorf("starting database: %w", err)
+		}
+		defer dbtest.StopDB(c)
 	}
-	defer dbtest.StopDB(c)
 
-	backend, err = ethereum.CreateSimulatedBackend(3, true, big.NewInt(100))
+	backend, err = ethereum.CreateSimulatedBackend(4, true, big.NewInt(100))
 	if err != nil {
 		return 1, fmt.Errorf("create backend: %w", err)
 	}
@@ -67,6 +78,11 @@ func run(m *testing.M) (int, error) {
 		return 1, fmt.Errorf("create player2Clt: %w", err)
 	}
 
+	player3Clt, err = ethereum.NewClient(backend, backend.PrivateKeys[3])
+	if err != nil {
+		return 1, fmt.Errorf("create player3Clt: %w", err)
+	}
+
 	return m.Run(), nil
 }
 
@@ -88,7 +104,7 @@ func Test_SuccessGamePlay(t *testing.T) {
 	// -------------------------------------------------------------------------
 	// Create game and check database
 
-	bank, engine := gameSetup(t, test)
+	bank, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic})
 
 	checkDatabase(ctx, t, "first round", engine)
 
@@ -424,7 +440,7 @@ func Test_InvalidBet(t *testing.T) {
 		test.Teardown()
 	}()
 
-	_, engine := gameSetup(t, test)
+	_, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic})
 
 	player1Addr := player1Clt.Address()
 	player2Addr := player2Clt.Address()
@@ -464,6 +480,33 @@ func Test_InvalidBet(t *testing.T) {
 	if err := engine.Bet(ctx, engine.State().PlayerTurn, 2, 6); err == nil {
 		t.Fatal("expecting error making an invalid bet")
 	}
+
+	// -------------------------------------------------------------------------
+	// Bets that are impossible with the dice in play return field errors.
+
+	tests := []struct {
+		name   string
+		number int
+		suit   int
+		field  string
+	}{
+		{name: "suit zero", number: 4, suit: 0, field: "suit"},
+		{name: "suit seven", number: 4, suit: 7, field: "suit"},
+		{name: "suit negative", number: 4, suit: -3, field: "suit"},
+		{name: "number zero", number: 0, suit: 4, field: "number"},
+		{name: "number over dice in play", number: 11, suit: 4, field: "number"},
+	}
+
+	for _, tt := range tests {
+		err := engine.Bet(ctx, engine.State().PlayerTurn, tt.number, tt.suit)
+		if !validate.IsFieldErrors(err) {
+			t.Fatalf("%s: expecting field errors; got %v", tt.name, err)
+		}
+
+		if _, exists := validate.GetFieldErrors(err).Fields()[tt.field]; !exists {
+			t.Fatalf("%s: expecting an error for field %s; got %v", tt.name, tt.field, err)
+		}
+	}
 }
 
 func Test_WrongPlayerTryingToPlay(t *testing.T) {
@@ -479,7 +522,7 @@ func Test_WrongPlayerTryingToPlay(t *testing.T) {
 		test.Teardown()
 	}()
 
-	_, engine := gameSetup(t, test)
+	_, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic})
 
 	player1Addr := player1Clt.Address()
 	player2Addr := player2Clt.Address()
@@ -554,10 +597,11 @@ func Test_GameWithoutEnoughPlayers(t *testing.T) {
 	}
 
 	const anteUSD = 5.0
-	game, err := game.New(ctx, test.Log, converter, store, bank, player1Clt.Address(), anteUSD)
+	game, err := game.New(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), player1Clt.Address(), anteUSD, game.Rules{Ruleset: game.RulesetClassic})
 	if err != nil {
 		t.Fatalf("unexpected error creating game: %s", err)
 	}
+	defer game.Abandon(ctx)
 
 	// -------------------------------------------------------------------------
 	// Start the game with only 1 player
@@ -596,62 +640,1188 @@ func Test_NewGameNotEnoughBalance(t *testing.T) {
 	}
 
 	const anteUSD = 5.0
-	_, err = game.New(ctx, test.Log, converter, store, bank, player1Clt.Address(), anteUSD)
+	_, err = game.New(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), player1Clt.Address(), anteUSD, game.Rules{Ruleset: game.RulesetClassic})
 	if err == nil {
 		t.Fatalf("expecting an error creating a game: %s", err)
 	}
 }
 
-// =============================================================================
+func Test_PerudoRules(t *testing.T) {
+	ctx, test := newTest(t, "PerudoRules")
 
-func gameSetup(t *testing.T, test *dbtest.Test) (*bank.Bank, *game.Game) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	rules, err := game.ParseRules(game.RulesetPerudo)
+	if err != nil {
+		t.Fatalf("unexpected error parsing rules: %s", err)
+	}
 
-	contractID := deployContract(t)
+	_, engine := gameSetup(t, test, rules)
 
-	converter := currency.NewDefaultConverter(scbank.BankMetaData.ABI)
+	if err := engine.StartGame(ctx); err != nil {
+		t.Fatalf("unexpected error starting the game: %s", err)
+	}
 
-	store := gamedb.NewStore(test.Log, test.DB)
+	// -------------------------------------------------------------------------
+	// Mocked roll dice so the wild ones make the bet true.
+
+	engine.RollDice(ctx, player1Clt.Address(), 1, 1, 4, 2, 2)
+	engine.RollDice(ctx, player2Clt.Address(), 1, 3, 5, 6, 6)
+
+	bettor := engine.State().PlayerTurn
+
+	if err := engine.Bet(ctx, bettor, 2, 1); err == nil {
+		t.Fatal("expecting error making an opening bet on wild ones")
+	}
+
+	if err := engine.Bet(ctx, bettor, 4, 4); err != nil {
+		t.Fatalf("unexpected error making bet: %s", err)
+	}
+
+	caller := engine.State().PlayerTurn
+
+	if err := engine.Bet(ctx, caller, 1, 1); err == nil {
+		t.Fatal("expecting error making a bet on ones below half the last bet")
+	}
 
 	// -------------------------------------------------------------------------
-	// Players need to deposit money into their accounts
+	// There are four 4's when the ones are counted so the bet is exact.
 
-	player1Bank, err := bank.New(ctx, test.Log, backend, player1Clt.PrivateKey(), contractID)
+	winner, loser, err := engine.CallExact(ctx, caller)
 	if err != nil {
-		t.Fatalf("creating new bank for player 1: %s", err)
+		t.Fatalf("unexpected error calling exact: %s", err)
 	}
 
-	player2Bank, err := bank.New(ctx, test.Log, backend, player2Clt.PrivateKey(), contractID)
+	if winner != caller {
+		t.Fatalf("expecting the caller to win the exact call; got '%s'", winner)
+	}
+
+	if loser != bettor {
+		t.Fatalf("expecting the bettor to lose the exact call; got '%s'", loser)
+	}
+
+	reveal := engine.State().Reveal
+
+	if reveal.Total != 4 || !reveal.Exact || reveal.Winner != caller || reveal.Loser != bettor {
+		t.Fatalf("expecting a reveal of 4 dice won by the caller; got %+v", reveal)
+	}
+
+	if len(reveal.Cups) != 2 {
+		t.Fatalf("expecting the reveal to have 2 cups; got %d", len(reveal.Cups))
+	}
+
+	// -------------------------------------------------------------------------
+	// The loser gives up a die for the next round.
+
+	if _, err := engine.NextRound(ctx); err != nil {
+		t.Fatalf("unexpected error starting new round: %s", err)
+	}
+
+	state := engine.State()
+
+	if n := len(state.Cups[loser].Dice); n != 4 {
+		t.Fatalf("expecting the loser to have 4 dice; got %d", n)
+	}
+
+	if n := len(state.Cups[winner].Dice); n != 5 {
+		t.Fatalf("expecting the winner to have 5 dice; got %d", n)
+	}
+
+	if err := engine.Bet(ctx, state.PlayerTurn, 10, 6); err == nil {
+		t.Fatal("expecting error making a bet with more dice than are in play")
+	}
+}
+
+func Test_RollOncePerRound(t *testing.T) {
+	ctx, test := newTest(t, "RollOncePerRound")
+
+	_, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic})
+
+	if err := engine.StartGame(ctx); err != nil {
+		t.Fatalf("unexpected error starting the game: %s", err)
+	}
+
+	player := player1Clt.Address()
+
+	if err := engine.RollDice(ctx, player); err != nil {
+		t.Fatalf("unexpected error rolling dice: %s", err)
+	}
+
+	cup := engine.State().Cups[player]
+
+	// -------------------------------------------------------------------------
+	// A new client seed can't be used to roll the dice again.
+
+	if err := engine.SetClientSeed(ctx, player, "another seed"); err != nil {
+		t.Fatalf("unexpected error setting the client seed: %s", err)
+	}
+
+	if err := engine.RollDice(ctx, player); err == nil {
+		t.Fatal("expecting error rolling the dice twice in a round")
+	}
+
+	if got := engine.State().Cups[player]; got.Commitment != cup.Commitment || got.ClientSeed != cup.ClientSeed {
+		t.Fatalf("expecting the roll to be kept; got commitment[%s] seed[%s]", got.Commitment, got.ClientSeed)
+	}
+
+	// -------------------------------------------------------------------------
+	// The client seed is used for the roll of the next round.
+
+	bettor := engine.State().PlayerTurn
+	if err := engine.Bet(ctx, bettor, 1, 2); err != nil {
+		t.Fatalf("unexpected error making bet: %s", err)
+	}
+
+	if _, _, err := engine.CallLiar(ctx, engine.State().PlayerTurn); err != nil {
+		t.Fatalf("unexpected error calling liar: %s", err)
+	}
+
+	if _, err := engine.NextRound(ctx); err != nil {
+		t.Fatalf("unexpected error starting new round: %s", err)
+	}
+
+	if seed := engine.State().Cups[player].ClientSeed; seed != "another seed" {
+		t.Fatalf("expecting the next round to be rolled with the new client seed; got %q", seed)
+	}
+
+	if err := engine.RollDice(ctx, player); err == nil {
+		t.Fatal("expecting error rolling the dice after the round was rolled")
+	}
+}
+
+func Test_TurnTimeout(t *testing.T) {
+	ctx, test := newTest(t, "TurnTimeout")
+
+	rules := game.Rules{
+		Ruleset:       game.RulesetClassic,
+		TurnTimeout:   time.Minute,
+		TimeoutAction: game.TimeoutBid,
+	}
+
+	_, engine := gameSetup(t, test, rules)
+
+	if err := engine.StartGame(ctx); err != nil {
+		t.Fatalf("unexpected error starting the game: %s", err)
+	}
+
+	engine.RollDice(ctx, player1Clt.Address())
+	engine.RollDice(ctx, player2Clt.Address())
+
+	if _, expired := engine.ExpireTurn(ctx, time.Now()); expired {
+		t.Fatal("expecting the turn not to be expired yet")
+	}
+
+	// -------------------------------------------------------------------------
+	// The expired turn places the minimum bet for the player.
+
+	player := engine.State().PlayerTurn
+
+	timeout, expired := engine.ExpireTurn(ctx, time.Now().Add(2*time.Minute))
+	if !expired {
+		t.Fatal("expecting the turn to be expired")
+	}
+
+	if timeout.Player != player || timeout.Action != game.TimeoutBid {
+		t.Fatalf("expecting a bid timeout for %s; got %+v", player, timeout)
+	}
+
+	state := engine.State()
+
+	if len(state.Bets) != 1 || state.Bets[0].Number != 1 || state.Bets[0].Suit != 1 {
+		t.Fatalf("expecting the minimum bet to be placed; got %+v", state.Bets)
+	}
+
+	if state.PlayerTurn == player {
+		t.Fatal("expecting the turn to move to the next player")
+	}
+}
+
+func Test_EventReplay(t *testing.T) {
+	ctx, test := newTest(t, "EventReplay")
+
+	_, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic})
+
+	if err := engine.StartGame(ctx); err != nil {
+		t.Fatalf("unexpected error starting the game: %s", err)
+	}
+
+	engine.RollDice(ctx, player1Clt.Address(), 6, 5, 3, 3, 3)
+	engine.RollDice(ctx, player2Clt.Address(), 1, 1, 4, 4, 2)
+
+	bettor := engine.State().PlayerTurn
+
+	if err := engine.Bet(ctx, bettor, 3, 3); err != nil {
+		t.Fatalf("unexpected error making bet: %s", err)
+	}
+
+	caller := engine.State().PlayerTurn
+
+	if _, _, err := engine.CallLiar(ctx, caller); err != nil {
+		t.Fatalf("unexpected error calling liar: %s", err)
+	}
+
+	if _, err := engine.NextRound(ctx); err != nil {
+		t.Fatalf("unexpected error starting new round: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// The state rebuilt from the stored events must match the game.
+
+	store := gamedb.NewStore(test.Log, test.DB)
+
+	events, err := store.QueryEvents(ctx, engine.ID())
 	if err != nil {
-		t.Fatalf("creating new bank for player 2: %s", err)
+		t.Fatalf("unexpected error querying events: %s", err)
 	}
 
-	initalDepositGwei := converter.USD2GWei(big.NewFloat(100))
-	_, _, err = player1Bank.Deposit(ctx, initalDepositGwei)
+	if len(events) != len(engine.Events()) {
+		t.Fatalf("expecting %d stored events; got %d", len(engine.Events()), len(events))
+	}
+
+	// The secret seeds are not stored with the events.
+	for _, e := range events {
+		if e.NextServerSeed != "" || (e.Type == game.EventNew && e.ServerSeed != "") {
+			t.Fatalf("expecting event %d of type %s to have no secret seeds", e.Sequence, e.Type)
+		}
+	}
+
+	seeds, err := store.QueryServerSeeds(ctx, engine.ID())
 	if err != nil {
-		t.Fatalf("depositing money into bank for player1: %s", err)
+		t.Fatalf("unexpected error querying server seeds: %s", err)
 	}
 
-	_, _, err = player2Bank.Deposit(ctx, initalDepositGwei)
+	if len(seeds) == 0 {
+		t.Fatal("expecting the secret seeds to be stored")
+	}
+
+	replayed, err := game.Apply(events)
 	if err != nil {
-		t.Fatalf("depositing money into bank for player2: %s", err)
+		t.Fatalf("unexpected error applying events: %s", err)
+	}
+
+	state := engine.State()
+
+	if replayed.Sequence != state.Sequence || state.Sequence != len(events) {
+		t.Fatalf("expecting sequence %d; got %d and %d", len(events), state.Sequence, replayed.Sequence)
+	}
+
+	if replayed.Status != state.Status || replayed.Round != state.Round {
+		t.Fatalf("expecting status %s round %d; got status %s round %d", state.Status, state.Round, replayed.Status, replayed.Round)
+	}
+
+	if replayed.PlayerTurn != state.PlayerTurn {
+		t.Fatalf("expecting player turn %s; got %s", state.PlayerTurn, replayed.PlayerTurn)
+	}
+
+	if replayed.PlayerLastOut != state.PlayerLastOut || replayed.PlayerLastWin != state.PlayerLastWin {
+		t.Fatalf("expecting last out %s and last win %s; got %s and %s", state.PlayerLastOut, state.PlayerLastWin, replayed.PlayerLastOut, replayed.PlayerLastWin)
+	}
+
+	if replayed.ServerSeedHash != state.ServerSeedHash {
+		t.Fatalf("expecting server seed hash %s; got %s", state.ServerSeedHash, replayed.ServerSeedHash)
+	}
+
+	for player, cup := range state.Cups {
+		rCup := replayed.Cups[player]
+
+		if rCup.Outs != cup.Outs || rCup.Commitment != cup.Commitment {
+			t.Fatalf("expecting cup %+v; got %+v", cup, rCup)
+		}
+	}
+}
+
+func Test_Replay(t *testing.T) {
+	ctx, test := newTest(t, "Replay")
+
+	_, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic})
+
+	if err := engine.StartGame(ctx); err != nil {
+		t.Fatalf("unexpected error starting the game: %s", err)
+	}
+
+	engine.RollDice(ctx, player1Clt.Address(), 6, 5, 3, 3, 3)
+	engine.RollDice(ctx, player2Clt.Address(), 1, 1, 4, 4, 2)
+
+	bettor := engine.State().PlayerTurn
+
+	if err := engine.Bet(ctx, bettor, 3, 3); err != nil {
+		t.Fatalf("unexpected error making bet: %s", err)
+	}
+
+	caller := engine.State().PlayerTurn
+
+	if _, _, err := engine.CallLiar(ctx, caller); err != nil {
+		t.Fatalf("unexpected error calling liar: %s", err)
+	}
+
+	if _, err := game.NewReplay(engine.Events()); err == nil {
+		t.Fatal("expecting an error replaying a game that is not over")
 	}
 
 	// -------------------------------------------------------------------------
-	// Create game and add players
+	// Put the loser out of the game in the next round so the game is over.
 
-	bank, err := bank.New(ctx, test.Log, backend, ownerClt.PrivateKey(), contractID)
+	loser := engine.State().PlayerLastOut
+
+	if _, err := engine.NextRound(ctx); err != nil {
+		t.Fatalf("unexpected error starting new round: %s", err)
+	}
+
+	if err := engine.ApplyOut(ctx, loser, 3); err != nil {
+		t.Fatalf("unexpected error applying outs: %s", err)
+	}
+
+	if _, err := engine.NextRound(ctx); err != nil {
+		t.Fatalf("unexpected error starting new round: %s", err)
+	}
+
+	rp, err := game.NewReplay(engine.Events())
 	if err != nil {
-		t.Fatalf("creating new bank for the engine: %s", err)
+		t.Fatalf("unexpected error building the replay: %s", err)
 	}
 
-	// Create a game and add player1 as first player in the game.
-	const anteUSD = 5.0
-	game, err := game.New(ctx, test.Log, converter, store, bank, player1Clt.Address(), anteUSD)
+	if rp.Version != game.ReplayVersion || rp.Status != game.StatusGameOver {
+		t.Fatalf("expecting version %d and status %s; got %d and %s", game.ReplayVersion, game.StatusGameOver, rp.Version, rp.Status)
+	}
+
+	if rp.Winner != engine.State().PlayerLastWin {
+		t.Fatalf("expecting winner %s; got %s", engine.State().PlayerLastWin, rp.Winner)
+	}
+
+	if len(rp.Rounds) != 2 {
+		t.Fatalf("expecting 2 rounds; got %d", len(rp.Rounds))
+	}
+
+	round := rp.Rounds[0]
+
+	if round.Result != game.EventLiar || round.Loser != loser || round.ServerSeed == "" {
+		t.Fatalf("expecting the round to end with a liar call; got %+v", round)
+	}
+
+	if len(round.Cups) != 2 || len(round.Bets) != 1 || round.Bets[0].Player != bettor {
+		t.Fatalf("expecting 2 cups and the bet of %s; got %+v", bettor, round)
+	}
+
+	if round.Total != 3 {
+		t.Fatalf("expecting 3 threes counted; got %d", round.Total)
+	}
+
+	round = rp.Rounds[1]
+
+	if round.Result != game.EventOut || round.Loser != loser || len(round.Bets) != 0 {
+		t.Fatalf("expecting the round to end with the loser out; got %+v", round)
+	}
+}
+
+func Test_LoadGame(t *testing.T) {
+	ctx, test := newTest(t, "LoadGame")
+
+	bank, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic})
+
+	if err := engine.StartGame(ctx); err != nil {
+		t.Fatalf("unexpected error starting the game: %s", err)
+	}
+
+	engine.RollDice(ctx, player1Clt.Address())
+	engine.RollDice(ctx, player2Clt.Address())
+
+	if err := engine.Bet(ctx, engine.State().PlayerTurn, 2, 3); err != nil {
+		t.Fatalf("unexpected error making bet: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// The game loaded from the store must continue where it left off.
+
+	converter := currency.NewDefaultConverter(scbank.BankMetaData.ABI)
+	store := gamedb.NewStore(test.Log, test.DB)
+
+	loaded, err := game.Load(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), engine.ID())
 	if err != nil {
-		t.Fatalf("unexpected error creating game: %s", err)
+		t.Fatalf("unexpected error loading the game: %s", err)
+	}
+
+	state := engine.State()
+	loadedState := loaded.State()
+
+	if loadedState.Status != state.Status || loadedState.Round != state.Round || loadedState.PlayerTurn != state.PlayerTurn {
+		t.Fatalf("expecting status %s round %d turn %s; got status %s round %d turn %s", state.Status, state.Round, state.PlayerTurn, loadedState.Status, loadedState.Round, loadedState.PlayerTurn)
+	}
+
+	if loadedState.NextServerSeedHash != state.NextServerSeedHash {
+		t.Fatalf("expecting the committed seed %s; got %s", state.NextServerSeedHash, loadedState.NextServerSeedHash)
+	}
+
+	for player, cup := range state.Cups {
+		if fmt.Sprint(loadedState.Cups[player].Dice) != fmt.Sprint(cup.Dice) {
+			t.Fatalf("expecting dice %v for %s; got %v", cup.Dice, player, loadedState.Cups[player].Dice)
+		}
+	}
+
+	if err := loaded.Bet(ctx, loadedState.PlayerTurn, 2, 4); err != nil {
+		t.Fatalf("unexpected error making bet on the loaded game: %s", err)
+	}
+
+	if _, _, err := loaded.CallLiar(ctx, loaded.State().PlayerTurn); err != nil {
+		t.Fatalf("unexpected error calling liar on the loaded game: %s", err)
+	}
+}
+
+func Test_ReaperAbandonsIdleGames(t *testing.T) {
+	ctx, test := newTest(t, "ReaperAbandonsIdleGames")
+
+	_, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic})
+
+	evictions := make(chan game.Eviction, 10)
+
+	reaper := game.NewReaper(test.Log, 10*time.Millisecond, time.Hour, 0, 5*time.Second, false)
+	reaper.Start(func(ctx context.Context, e game.Eviction) {
+		evictions <- e
+	})
+	defer reaper.Shutdown()
+
+	for {
+		select {
+		case e := <-evictions:
+			if e.GameID != engine.ID() {
+				continue
+			}
+
+			if e.Reason != game.EvictAbandoned {
+				t.Fatalf("expecting the game to be abandoned; got %s", e.Reason)
+			}
+
+			if status := engine.Status(); status != game.StatusAbandoned {
+				t.Fatalf("expecting status %s; got %s", game.StatusAbandoned, status)
+			}
+
+			if _, err := game.Tables.Retrieve(ctx, engine.ID()); err == nil {
+				t.Fatal("expecting the game to be evicted from the tables")
+			}
+
+			return
+
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the game to be evicted")
+		}
 	}
+}
+
+func Test_ReaperReconcilesFinishedGames(t *testing.T) {
+	ctx, test := newTest(t, "ReaperReconcilesFinishedGames")
+
+	_, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic})
+
+	// -------------------------------------------------------------------------
+	// The game is over once a player leaves, but no one reconciles it.
+
+	if err := engine.StartGame(ctx); err != nil {
+		t.Fatalf("unexpected error starting the game: %s", err)
+	}
+
+	if err := engine.Leave(ctx, player2Clt.Address()); err != nil {
+		t.Fatalf("unexpected error leaving the game: %s", err)
+	}
+
+	if status := engine.Status(); status != game.StatusGameOver {
+		t.Fatalf("expecting status %s; got %s", game.StatusGameOver, status)
+	}
+
+	evictions := make(chan game.Eviction, 10)
+
+	reaper := game.NewReaper(test.Log, 10*time.Millisecond, time.Hour, 0, 5*time.Second, true)
+	reaper.Start(func(ctx context.Context, e game.Eviction) {
+		evictions <- e
+	})
+	defer reaper.Shutdown()
+
+	for {
+		select {
+		case e := <-evictions:
+			if e.GameID != engine.ID() {
+				continue
+			}
+
+			if e.Reason != game.EvictReconciled {
+				t.Fatalf("expecting the game to be reconciled; got %s", e.Reason)
+			}
+
+			if status := engine.Status(); status != game.StatusReconciled {
+				t.Fatalf("expecting status %s; got %s", game.StatusReconciled, status)
+			}
+
+			for _, player := range []common.Address{player1Clt.Address(), player2Clt.Address()} {
+				if reserved := game.Escrow.Reserved(player); reserved.Sign() != 0 {
+					t.Fatalf("expecting the ante of %s to be released; got %v", player, reserved)
+				}
+			}
+
+			if _, err := game.Tables.Retrieve(ctx, engine.ID()); err == nil {
+				t.Fatal("expecting the game to be evicted from the tables")
+			}
+
+			return
+
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the game to be evicted")
+		}
+	}
+}
+
+func Test_EscrowReservesAnte(t *testing.T) {
+	ctx, test := newTest(t, "EscrowReservesAnte")
+
+	bank, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic})
+
+	converter := currency.NewDefaultConverter(scbank.BankMetaData.ABI)
+	store := gamedb.NewStore(test.Log, test.DB)
+	rules := game.Rules{Ruleset: game.RulesetClassic}
+
+	// -------------------------------------------------------------------------
+	// The players deposited $100 and $5 is reserved for the first game.
+
+	if _, err := game.New(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), player1Clt.Address(), 96, rules); err == nil {
+		t.Fatal("expecting error creating a game with an ante above the available balance")
+	}
+
+	second, err := game.New(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), player1Clt.Address(), 95, rules)
+	if err != nil {
+		t.Fatalf("unexpected error creating a game with the available balance: %s", err)
+	}
+
+	defer second.Abandon(ctx)
+
+	if err := second.AddAccount(ctx, player2Clt.Address()); err != nil {
+		t.Fatalf("unexpected error adding player 2 to the second game: %s", err)
+	}
+
+	if _, err := game.New(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), player1Clt.Address(), 5, rules); err == nil {
+		t.Fatal("expecting error creating a game without available balance")
+	}
+
+	// -------------------------------------------------------------------------
+	// Abandoning the first game makes its ante available again.
+
+	if err := engine.Abandon(ctx); err != nil {
+		t.Fatalf("unexpected error abandoning the first game: %s", err)
+	}
+
+	third, err := game.New(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), player1Clt.Address(), 5, rules)
+	if err != nil {
+		t.Fatalf("unexpected error creating a game after the ante was released: %s", err)
+	}
+	defer third.Abandon(ctx)
+
+	if err := third.AddAccount(ctx, player2Clt.Address()); err != nil {
+		t.Fatalf("unexpected error adding a player after the ante was released: %s", err)
+	}
+}
+
+func Test_TableSettings(t *testing.T) {
+	ctx, test := newTest(t, "TableSettings")
+
+	bank, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic, MinPlayers: 2, MaxPlayers: 2})
+
+	converter := currency.NewDefaultConverter(scbank.BankMetaData.ABI)
+	store := gamedb.NewStore(test.Log, test.DB)
+
+	// -------------------------------------------------------------------------
+	// The table only has two seats.
+
+	if err := engine.AddAccount(ctx, ownerClt.Address()); err == nil {
+		t.Fatal("expecting error adding a player to a full table")
+	}
+
+	state := engine.State()
+
+	if state.AnteUSD != 5 {
+		t.Fatalf("expecting the ante to be $5; got $%v", state.AnteUSD)
+	}
+
+	// -------------------------------------------------------------------------
+	// The settings are restored when the game is loaded.
+
+	loaded, err := game.Load(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), engine.ID())
+	if err != nil {
+		t.Fatalf("unexpected error loading the game: %s", err)
+	}
+
+	if got := loaded.State(); got.Rules.MaxPlayers != 2 || got.AnteUSD != 5 {
+		t.Fatalf("expecting the settings to be restored; got max[%d] ante[%v]", got.Rules.MaxPlayers, got.AnteUSD)
+	}
+
+	// -------------------------------------------------------------------------
+	// Settings outside the limits are rejected.
+
+	invalid := []game.Rules{
+		{Ruleset: game.RulesetClassic, MaxPlayers: 6},
+		{Ruleset: game.RulesetClassic, MinPlayers: 1},
+		{Ruleset: game.RulesetClassic, MinPlayers: 4, MaxPlayers: 3},
+	}
+
+	for _, rules := range invalid {
+		if _, err := game.New(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), player1Clt.Address(), 5, rules); err == nil {
+			t.Fatalf("expecting error creating a game with min[%d] max[%d]", rules.MinPlayers, rules.MaxPlayers)
+		}
+	}
+
+	if _, err := game.New(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), player1Clt.Address(), 0, game.Rules{Ruleset: game.RulesetClassic}); err == nil {
+		t.Fatal("expecting error creating a game without an ante")
+	}
+
+	// -------------------------------------------------------------------------
+	// A table that requires three players can't start with two.
+
+	three, err := game.New(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), player1Clt.Address(), 5, game.Rules{Ruleset: game.RulesetClassic, MinPlayers: 3})
+	if err != nil {
+		t.Fatalf("unexpected error creating a game: %s", err)
+	}
+	defer three.Abandon(ctx)
+
+	if err := three.AddAccount(ctx, player2Clt.Address()); err != nil {
+		t.Fatalf("unexpected error adding player 2: %s", err)
+	}
+
+	if err := three.StartGame(ctx); err == nil {
+		t.Fatal("expecting error starting a game without the min players")
+	}
+}
+
+func Test_Leave(t *testing.T) {
+	ctx, test := newTest(t, "Leave")
+
+	bank, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic})
+
+	converter := currency.NewDefaultConverter(scbank.BankMetaData.ABI)
+	store := gamedb.NewStore(test.Log, test.DB)
+
+	// -------------------------------------------------------------------------
+	// Leaving before the game starts gives up the seat and the ante.
+
+	if err := engine.Leave(ctx, player2Clt.Address()); err != nil {
+		t.Fatalf("unexpected error leaving the game: %s", err)
+	}
+
+	if reserved := game.Escrow.Reserved(player2Clt.Address()); reserved.Sign() != 0 {
+		t.Fatalf("expecting the ante to be released; got %v", reserved)
+	}
+
+	state := engine.State()
+
+	if len(state.ExistingPlayers) != 1 || state.ExistingPlayers[0] != player1Clt.Address() {
+		t.Fatalf("expecting only player 1 to be seated; got %v", state.ExistingPlayers)
+	}
+
+	if err := engine.Leave(ctx, player2Clt.Address()); !errors.Is(err, game.ErrNotSeated) {
+		t.Fatalf("expecting not seated error leaving a game the player isn't in; got %v", err)
+	}
+
+	if err := engine.Leave(ctx, common.Address{}); !errors.Is(err, game.ErrNotSeated) {
+		t.Fatalf("expecting not seated error leaving with the zero address; got %v", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// Leaving after the game starts forfeits the game.
+
+	if err := engine.AddAccount(ctx, player2Clt.Address()); err != nil {
+		t.Fatalf("unexpected error adding player 2 back: %s", err)
+	}
+
+	if err := engine.StartGame(ctx); err != nil {
+		t.Fatalf("unexpected error starting the game: %s", err)
+	}
+
+	leaving := engine.State().PlayerTurn
+
+	if err := engine.Leave(ctx, leaving); err != nil {
+		t.Fatalf("unexpected error leaving the game: %s", err)
+	}
+
+	state = engine.State()
+
+	if state.Status != game.StatusGameOver {
+		t.Fatalf("expecting the game to be over; got %s", state.Status)
+	}
+
+	if state.PlayerLastWin == leaving || state.PlayerLastWin != state.PlayerTurn {
+		t.Fatalf("expecting the player left at the table to win; got %s", state.PlayerLastWin)
+	}
+
+	if state.Cups[leaving].Outs != state.Rules.MaxOuts() {
+		t.Fatalf("expecting the player who left to have the max outs; got %d", state.Cups[leaving].Outs)
+	}
+
+	// -------------------------------------------------------------------------
+	// The events rebuild the same state.
+
+	loaded, err := game.Load(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), engine.ID())
+	if err != nil {
+		t.Fatalf("unexpected error loading the game: %s", err)
+	}
+
+	if got := loaded.State(); got.Status != state.Status || got.PlayerLastWin != state.PlayerLastWin || len(got.ExistingPlayers) != 2 {
+		t.Fatalf("expecting the loaded state to match; got status[%s] winner[%s] players[%d]", got.Status, got.PlayerLastWin, len(got.ExistingPlayers))
+	}
+
+	if _, _, err := engine.Reconcile(ctx); err != nil {
+		t.Fatalf("unexpected error reconciling the game: %s", err)
+	}
+}
+
+func Test_LeaveAfterBetting(t *testing.T) {
+	ctx, test := newTest(t, "LeaveAfterBetting")
+
+	rules, err := game.ParseRules(game.RulesetPerudo)
+	if err != nil {
+		t.Fatalf("unexpected error parsing rules: %s", err)
+	}
+
+	engineBank, engine := gameSetup(t, test, rules)
+
+	converter := currency.NewDefaultConverter(scbank.BankMetaData.ABI)
+	store := gamedb.NewStore(test.Log, test.DB)
+
+	// -------------------------------------------------------------------------
+	// A third player is needed so the game goes on after a player leaves.
+
+	player3Bank, err := bank.New(ctx, test.Log, backend, player3Clt.PrivateKey(), engineBank.ContractID())
+	if err != nil {
+		t.Fatalf("creating new bank for player 3: %s", err)
+	}
+
+	if _, _, err := player3Bank.Deposit(ctx, converter.USD2GWei(big.NewFloat(100))); err != nil {
+		t.Fatalf("depositing money into bank for player3: %s", err)
+	}
+
+	if err := engine.AddAccount(ctx, player3Clt.Address()); err != nil {
+		t.Fatalf("unexpected error adding player 3: %s", err)
+	}
+
+	if err := engine.StartGame(ctx); err != nil {
+		t.Fatalf("unexpected error starting the game: %s", err)
+	}
+
+	// Every player has two 2's.
+	for _, clt := range []*ethereum.Client{player1Clt, player2Clt, player3Clt} {
+		if err := engine.RollDice(ctx, clt.Address(), 2, 2, 3, 3, 4); err != nil {
+			t.Fatalf("unexpected error rolling dice: %s", err)
+		}
+	}
+
+	// -------------------------------------------------------------------------
+	// The last bettor leaves before their bet is called.
+
+	bettor := engine.State().PlayerTurn
+
+	if err := engine.Bet(ctx, bettor, 2, 2); err != nil {
+		t.Fatalf("unexpected error making bet: %s", err)
+	}
+
+	leaving := engine.State().PlayerTurn
+
+	if err := engine.Bet(ctx, leaving, 5, 2); err != nil {
+		t.Fatalf("unexpected error making bet: %s", err)
+	}
+
+	if err := engine.Leave(ctx, leaving); err != nil {
+		t.Fatalf("unexpected error leaving the game: %s", err)
+	}
+
+	state := engine.State()
+
+	if len(state.Bets) != 1 || state.Bets[0].Player != bettor {
+		t.Fatalf("expecting only the bet of the player still playing; got %+v", state.Bets)
+	}
+
+	// -------------------------------------------------------------------------
+	// The call is on the bet of the player still playing. There are four 2's
+	// left in play so the bet stands.
+
+	caller := state.PlayerTurn
+
+	winner, loser, err := engine.CallLiar(ctx, caller)
+	if err != nil {
+		t.Fatalf("unexpected error calling liar: %s", err)
+	}
+
+	if winner != bettor || loser != caller {
+		t.Fatalf("expecting the bettor to win and the caller to lose; got winner[%s] loser[%s]", winner, loser)
+	}
+
+	if outs := engine.State().Cups[leaving].Outs; outs != rules.MaxOuts() {
+		t.Fatalf("expecting the player who left to have the max outs; got %d", outs)
+	}
+
+	if _, err := engine.NextRound(ctx); err != nil {
+		t.Fatalf("unexpected error starting new round: %s", err)
+	}
+
+	state = engine.State()
+
+	if n := len(state.Cups[leaving].Dice); n != 0 {
+		t.Fatalf("expecting the player who left to have no dice; got %d", n)
+	}
+
+	// -------------------------------------------------------------------------
+	// The events rebuild the same state.
+
+	loaded, err := game.Load(ctx, test.Log, converter, store, engineBank, game.NewSeededDicer(1), engine.ID())
+	if err != nil {
+		t.Fatalf("unexpected error loading the game: %s", err)
+	}
+
+	for player, cup := range state.Cups {
+		if got := loaded.State().Cups[player]; got.Outs != cup.Outs {
+			t.Fatalf("expecting player %s to have %d outs; got %d", player, cup.Outs, got.Outs)
+		}
+	}
+}
+
+func Test_BotsReconcile(t *testing.T) {
+	ctx, test := newTest(t, "BotsReconcile")
+
+	contractID := deployContract(t)
+
+	converter := currency.NewDefaultConverter(scbank.BankMetaData.ABI)
+	store := gamedb.NewStore(test.Log, test.DB)
+
+	// -------------------------------------------------------------------------
+	// The engine backs the seats of the bots with its own balance.
+
+	engineBank, err := bank.New(ctx, test.Log, backend, ownerClt.PrivateKey(), contractID)
+	if err != nil {
+		t.Fatalf("creating new bank for the engine: %s", err)
+	}
+
+	player1Bank, err := bank.New(ctx, test.Log, backend, player1Clt.PrivateKey(), contractID)
+	if err != nil {
+		t.Fatalf("creating new bank for player 1: %s", err)
+	}
+
+	initalDepositGwei := converter.USD2GWei(big.NewFloat(100))
+
+	if _, _, err := engineBank.Deposit(ctx, initalDepositGwei); err != nil {
+		t.Fatalf("depositing money into bank for the engine: %s", err)
+	}
+
+	if _, _, err := player1Bank.Deposit(ctx, initalDepositGwei); err != nil {
+		t.Fatalf("depositing money into bank for player1: %s", err)
+	}
+
+	bots := bot.New(test.Log, ownerClt.Address(), game.NewSeededDicer(1), time.Hour, 5*time.Second)
+
+	const anteUSD = 5.0
+	engine, err := game.New(ctx, test.Log, converter, store, bots.Banker(engineBank), game.NewSeededDicer(1), player1Clt.Address(), anteUSD, game.Rules{Ruleset: game.RulesetClassic})
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %s", err)
+	}
+
+	t.Cleanup(func() {
+		engine.Abandon(context.Background())
+	})
+
+	players, err := bots.Add(ctx, engine, 1, bot.StrategyProbability)
+	if err != nil {
+		t.Fatalf("unexpected error adding a bot: %s", err)
+	}
+	botAcct := players[0]
+
+	// -------------------------------------------------------------------------
+	// The player leaves so the bot wins the game.
+
+	if err := engine.StartGame(ctx); err != nil {
+		t.Fatalf("unexpected error starting the game: %s", err)
+	}
+
+	if err := engine.Leave(ctx, player1Clt.Address()); err != nil {
+		t.Fatalf("unexpected error leaving the game: %s", err)
+	}
+
+	if winner := engine.State().PlayerLastWin; winner != botAcct {
+		t.Fatalf("expecting the bot to win the game; got %s", winner)
+	}
+
+	if _, _, err := engine.Reconcile(ctx); err != nil {
+		t.Fatalf("unexpected error reconciling the game: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// The pot is paid to the engine, not the address of the bot.
+
+	anteWei := converter.USD2Wei(big.NewFloat(anteUSD))
+	initalDepositWei := converter.USD2Wei(big.NewFloat(100))
+
+	engineBalance, err := engineBank.Balance(ctx)
+	if err != nil {
+		t.Fatalf("unexpected to retrieve the balance of the bank owner: %s", err)
+	}
+
+	botBalance, err := engineBank.AccountBalance(ctx, botAcct)
+	if err != nil {
+		t.Fatalf("unexpected to retrieve the balance of the bot: %s", err)
+	}
+
+	player1Balance, err := engineBank.AccountBalance(ctx, player1Clt.Address())
+	if err != nil {
+		t.Fatalf("unexpected to retrieve the balance of player 1: %s", err)
+	}
+
+	// The engine is paid the pot for the bot and the game fee.
+	got := currency.GWei2Wei(engineBalance)
+	exp := big.NewInt(0).Add(initalDepositWei, big.NewInt(0).Mul(anteWei, big.NewInt(2)))
+	if got.Cmp(exp) != 0 {
+		t.Errorf("expecting 'engine' to have a balance of %d WEI; got %d WEI", exp, got)
+	}
+
+	if got := currency.GWei2Wei(botBalance); got.Sign() != 0 {
+		t.Errorf("expecting 'bot' to have no balance; got %d WEI", got)
+	}
+
+	got = currency.GWei2Wei(player1Balance)
+	exp = big.NewInt(0).Sub(initalDepositWei, anteWei)
+	if got.Cmp(exp) != 0 {
+		t.Errorf("expecting 'player1' to have a balance of %d WEI; got %d WEI", exp, got)
+	}
+}
+
+func Test_Rematch(t *testing.T) {
+	ctx, test := newTest(t, "Rematch")
+
+	_, engine := gameSetup(t, test, game.Rules{Ruleset: game.RulesetClassic, TurnTimeout: time.Minute})
+
+	player1 := player1Clt.Address()
+	player2 := player2Clt.Address()
+
+	if _, err := engine.AnswerRematch(ctx, player1, true); err == nil {
+		t.Fatal("expecting error answering a rematch before the game is reconciled")
+	}
+
+	// -------------------------------------------------------------------------
+	// End the game quickly by having a player leave.
+
+	if err := engine.StartGame(ctx); err != nil {
+		t.Fatalf("unexpected error starting the game: %s", err)
+	}
+
+	if err := engine.Leave(ctx, engine.State().PlayerTurn); err != nil {
+		t.Fatalf("unexpected error leaving the game: %s", err)
+	}
+
+	if _, _, err := engine.Reconcile(ctx); err != nil {
+		t.Fatalf("unexpected error reconciling the game: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// The rematch waits for every player to answer.
+
+	rm, err := engine.AnswerRematch(ctx, player2, true)
+	if err != nil {
+		t.Fatalf("unexpected error answering the rematch: %s", err)
+	}
+
+	if len(rm.Pending) != 1 || rm.Pending[0] != player1 {
+		t.Fatalf("expecting player 1 to be pending; got %v", rm.Pending)
+	}
+
+	if _, err := engine.StartRematch(ctx); err == nil {
+		t.Fatal("expecting error starting a rematch with a pending player")
+	}
+
+	// -------------------------------------------------------------------------
+	// The players who don't answer in a turn decline the rematch.
+
+	if _, expired := engine.ExpireRematch(ctx, time.Now()); expired {
+		t.Fatal("expecting the rematch not to expire before the deadline")
+	}
+
+	rm, expired := engine.ExpireRematch(ctx, time.Now().Add(2*time.Minute))
+	if !expired {
+		t.Fatal("expecting the rematch to expire after the deadline")
+	}
+
+	if len(rm.Pending) != 0 || len(rm.Declined) != 1 || rm.Declined[0] != player1 {
+		t.Fatalf("expecting player 1 to decline; got %+v", rm)
+	}
+
+	// A player can still change their answer until the rematch has started.
+
+	if _, err := engine.AnswerRematch(ctx, player1, true); err != nil {
+		t.Fatalf("unexpected error answering the rematch: %s", err)
+	}
+
+	rematch, err := engine.StartRematch(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error starting the rematch: %s", err)
+	}
+	defer rematch.Abandon(ctx)
+
+	// -------------------------------------------------------------------------
+	// The new game keeps the seating order and the ante.
+
+	state := rematch.State()
+
+	if state.Status != game.StatusPlaying {
+		t.Fatalf("expecting the rematch to be playing; got %s", state.Status)
+	}
+
+	if len(state.ExistingPlayers) != 2 || state.ExistingPlayers[0] != player1 || state.ExistingPlayers[1] != player2 {
+		t.Fatalf("expecting the seating order to be kept; got %v", state.ExistingPlayers)
+	}
+
+	if state.AnteUSD != engine.State().AnteUSD {
+		t.Fatalf("expecting the ante to be kept; got $%v", state.AnteUSD)
+	}
+
+	if got := engine.Rematch(); got.NewGameID != rematch.ID() {
+		t.Fatalf("expecting the rematch game id %s; got %s", rematch.ID(), got.NewGameID)
+	}
+
+	if _, err := engine.AnswerRematch(ctx, player2, false); err == nil {
+		t.Fatal("expecting error answering a rematch that already started")
+	}
+}
+
+func Test_SeededDicer(t *testing.T) {
+	d1 := game.NewSeededDicer(42)
+	d2 := game.NewSeededDicer(42)
+
+	for i := 0; i < 100; i++ {
+		v1 := d1.Roll(6)
+		v2 := d2.Roll(6)
+
+		if v1 != v2 {
+			t.Fatalf("expecting the same value for the same seed at roll %d; got %d and %d", i, v1, v2)
+		}
+
+		if v1 < 1 || v1 > 6 {
+			t.Fatalf("expecting a value between 1 and 6; got %d", v1)
+		}
+	}
+}
+
+func Test_CryptoDicer(t *testing.T) {
+	d := game.NewCryptoDicer()
+
+	for i := 0; i < 100; i++ {
+		if v := d.Roll(6); v < 1 || v > 6 {
+			t.Fatalf("expecting a value between 1 and 6; got %d", v)
+		}
+	}
+}
+
+func Test_Stats(t *testing.T) {
+	player := common.HexToAddress("0x8e113078adf6888b7ba84967f299f29aece24c55")
+
+	cups := []game.Cup{
+		{Dice: []int{1, 3, 3, 5, 6}},
+		{Dice: []int{1, 1, 3, 4, 6}},
+	}
+
+	classic := game.Rules{Ruleset: game.RulesetClassic}
+	perudo, err := game.ParseRules(game.RulesetPerudo)
+	if err != nil {
+		t.Fatalf("should be able to parse the rules: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		rules    game.Rules
+		palifico bool
+		bet      game.Bet
+		bluff    bool
+	}{
+		{name: "classic true", rules: classic, bet: game.Bet{Player: player, Number: 3, Suit: 3}, bluff: false},
+		{name: "classic false", rules: classic, bet: game.Bet{Player: player, Number: 4, Suit: 3}, bluff: true},
+		{name: "wild ones", rules: perudo, bet: game.Bet{Player: player, Number: 6, Suit: 3}, bluff: false},
+		{name: "palifico", rules: perudo, palifico: true, bet: game.Bet{Player: player, Number: 6, Suit: 3}, bluff: true},
+	}
+
+	for _, tt := range tests {
+		if bluff := tt.rules.Bluff(tt.bet, cups, tt.palifico); bluff != tt.bluff {
+			t.Fatalf("%s: expecting bluff to be %v; got %v", tt.name, tt.bluff, bluff)
+		}
+	}
+
+	stats := game.Stats{Games: 4, Wins: 1, Calls: 0, Bets: 10, Bluffs: 3}
+
+	if stats.WinRate() != 0.25 {
+		t.Fatalf("expecting a win rate of 0.25; got %v", stats.WinRate())
+	}
+
+	if stats.CallRate() != 0 {
+		t.Fatalf("expecting a call rate of 0 without calls; got %v", stats.CallRate())
+	}
+
+	if stats.BluffRate() != 0.3 {
+		t.Fatalf("expecting a bluff rate of 0.3; got %v", stats.BluffRate())
+	}
+}
+
+// =============================================================================
+
+// newTest starts a test against its own database. The database is removed
+// and the context is canceled when the test finishes.
+func newTest(t *testing.T, name string) (context.Context, *dbtest.Test) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	test := dbtest.NewTest(t, c, name)
+	t.Cleanup(test.Teardown)
+
+	return ctx, test
+}
+
+func gameSetup(t *testing.T, test *dbtest.Test, rules game.Rules) (*bank.Bank, *game.Game) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	contractID := deployContract(t)
+
+	converter := currency.NewDefaultConverter(scbank.BankMetaData.ABI)
+
+	store := gamedb.NewStore(test.Log, test.DB)
+
+	// -------------------------------------------------------------------------
+	// Players need to deposit money into their accounts
+
+	player1Bank, err := bank.New(ctx, test.Log, backend, player1Clt.PrivateKey(), contractID)
+	if err != nil {
+		t.Fatalf("creating new bank for player 1: %s", err)
+	}
+
+	player2Bank, err := bank.New(ctx, test.Log, backend, player2Clt.PrivateKey(), contractID)
+	if err != nil {
+		t.Fatalf("creating new bank for player 2: %s", err)
+	}
+
+	initalDepositGwei := converter.USD2GWei(big.NewFloat(100))
+	_, _, err = player1Bank.Deposit(ctx, initalDepositGwei)
+	if err != nil {
+		t.Fatalf("depositing money into bank for player1: %s", err)
+	}
+
+	_, _, err = player2Bank.Deposit(ctx, initalDepositGwei)
+	if err != nil {
+		t.Fatalf("depositing money into bank for player2: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// Create game and add players
+
+	bank, err := bank.New(ctx, test.Log, backend, ownerClt.PrivateKey(), contractID)
+	if err != nil {
+		t.Fatalf("creating new bank for the engine: %s", err)
+	}
+
+	// Create a game and add player1 as first player in the game.
+	const anteUSD = 5.0
+	game, err := game.New(ctx, test.Log, converter, store, bank, game.NewSeededDicer(1), player1Clt.Address(), anteUSD, rules)
+	if err != nil {
+		t.Fatalf("unexpected error creating game: %s", err)
+	}
+
+	// The escrow is shared by all the tests, so release the antes reserved
+	// for games that were not reconciled.
+	t.Cleanup(func() {
+		game.Abandon(context.Background())
+	})
 
 	// Add player2 as the second player in the game.
 	err = game.AddAccount(ctx, player2Clt.Address())
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the fields the listing of past games can be filtered by.
+// Fields left as their zero value are not filtered on.
+type QueryFilter struct {
+	Player    common.Address // A player who sat at the table.
+	Status    string         // The current status of the game.
+	StartDate time.Time      // Games created at or after this time.
+	EndDate   time.Time      // Games created before this time.
+	Viewer    common.Address // Private games are only listed for their players.
+}
+
+// Summary represents a game in the listing of past games.
+type Summary struct {
+	GameID      uuid.UUID
+	DateCreated time.Time
+	AnteUSD     float64
+	Ruleset     string
+	Private     bool
+	Status      string
+	Round       int
+	Players     []common.Address
+	Winner      common.Address // Only known once the game is reconciled.
+}
+
+// Cursor marks the position after the last game of a page in the listing of
+// past games, which is ordered from newest to oldest. The zero value starts
+// from the newest game.
+type Cursor struct {
+	DateCreated time.Time
+	GameID      uuid.UUID
+}
+
+// NewCursor returns the cursor that continues the listing after the game.
+func NewCursor(s Summary) Cursor {
+	return Cursor{
+		DateCreated: s.DateCreated,
+		GameID:      s.GameID,
+	}
+}
+
+// ParseCursor decodes a cursor returned by the String method.
+func ParseCursor(str string) (Cursor, error) {
+	if str == "" {
+		return Cursor{}, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("decode cursor: %w", err)
+	}
+
+	date, id, found := strings.Cut(string(b), "|")
+	if !found {
+		return Cursor{}, errors.New("invalid cursor")
+	}
+
+	dateCreated, err := time.Parse(time.RFC3339Nano, date)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("parse cursor date: %w", err)
+	}
+
+	gameID, err := uuid.Parse(id)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("parse cursor game id: %w", err)
+	}
+
+	return Cursor{DateCreated: dateCreated, GameID: gameID}, nil
+}
+
+// IsZero reports if the cursor starts from the newest game.
+func (c Cursor) IsZero() bool {
+	return c.GameID == uuid.Nil
+}
+
+// String encodes the cursor so it can be handed to a client.
+func (c Cursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+
+	str := c.DateCreated.UTC().Format(time.RFC3339Nano) + "|" + c.GameID.String()
+
+	return base64.RawURLEncoding.EncodeToString([]byte(str))
+}
@@ -0,0 +1,48 @@
+package game_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/google/uuid"
+)
+
+func Test_Cursor(t *testing.T) {
+	sum := game.Summary{
+		GameID:      uuid.New(),
+		DateCreated: time.Date(2023, time.March, 4, 10, 30, 15, 123456789, time.UTC),
+	}
+
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		exp    game.Cursor
+		valid  bool
+	}{
+		{name: "round trip", cursor: game.NewCursor(sum).String(), exp: game.NewCursor(sum), valid: true},
+		{name: "empty", cursor: "", exp: game.Cursor{}, valid: true},
+		{name: "not base64", cursor: "not-a-cursor!"},
+		{name: "no separator", cursor: encode("2023-03-04T10:30:15Z")},
+		{name: "bad date", cursor: encode("yesterday|" + sum.GameID.String())},
+		{name: "bad game id", cursor: encode("2023-03-04T10:30:15Z|game")},
+	}
+
+	for _, tt := range tests {
+		got, err := game.ParseCursor(tt.cursor)
+
+		switch {
+		case tt.valid && err != nil:
+			t.Fatalf("%s: unexpected error parsing cursor: %s", tt.name, err)
+		case tt.valid && (got.GameID != tt.exp.GameID || !got.DateCreated.Equal(tt.exp.DateCreated)):
+			t.Fatalf("%s: expecting cursor %v; got %v", tt.name, tt.exp, got)
+		case !tt.valid && err == nil:
+			t.Fatalf("%s: expecting an error parsing the cursor", tt.name)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// InviteCode returns the code that lets a player join a private game. The
+// code contains the game id and is signed with the key, so a code for one
+// game can't be changed into a code for another game.
+func InviteCode(key []byte, gameID uuid.UUID) string {
+	return gameID.String() + "." + inviteSignature(key, gameID)
+}
+
+// ParseInviteCode checks the code was signed with the key and returns the id
+// of the game the code was created for.
+func ParseInviteCode(key []byte, code string) (uuid.UUID, error) {
+	id, signature, found := strings.Cut(code, ".")
+	if !found {
+		return uuid.UUID{}, errors.New("invite code is malformed")
+	}
+
+	gameID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("invite code game id: %w", err)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(inviteSignature(key, gameID))) {
+		return uuid.UUID{}, errors.New("invite code signature is invalid")
+	}
+
+	return gameID, nil
+}
+
+// inviteSignature returns the signature of the game id using the key.
+func inviteSignature(key []byte, gameID uuid.UUID) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(gameID[:])
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:9])
+}
@@ -0,0 +1,62 @@
+package game_test
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+func Test_InviteCode(t *testing.T) {
+	key := []byte("invite key")
+	gameID := uuid.New()
+	code := game.InviteCode(key, gameID)
+
+	tests := []struct {
+		name  string
+		key   []byte
+		code  string
+		valid bool
+	}{
+		{name: "valid", key: key, code: code, valid: true},
+		{name: "other key", key: []byte("other key"), code: code},
+		{name: "other game", key: key, code: uuid.New().String() + code[len(gameID.String()):]},
+		{name: "no signature", key: key, code: gameID.String()},
+		{name: "bad game id", key: key, code: "game" + code[len(gameID.String()):]},
+	}
+
+	for _, tt := range tests {
+		id, err := game.ParseInviteCode(tt.key, tt.code)
+
+		switch {
+		case tt.valid && err != nil:
+			t.Fatalf("%s: should be able to parse the invite code: %s", tt.name, err)
+		case tt.valid && id != gameID:
+			t.Fatalf("%s: expecting game id %s; got %s", tt.name, gameID, id)
+		case !tt.valid && err == nil:
+			t.Fatalf("%s: expecting an error parsing the invite code", tt.name)
+		}
+	}
+}
+
+func Test_Allowlist(t *testing.T) {
+	player := common.HexToAddress("0x8e113078adf6888b7ba84967f299f29aece24c55")
+	other := common.HexToAddress("0x0070742ff6003c3e809e78d524f0fe5dcc5ba7f7")
+
+	tests := []struct {
+		name    string
+		rules   game.Rules
+		player  common.Address
+		allowed bool
+	}{
+		{name: "on the allowlist", rules: game.Rules{Private: true, Allowlist: []common.Address{player}}, player: player, allowed: true},
+		{name: "not on the allowlist", rules: game.Rules{Private: true, Allowlist: []common.Address{player}}, player: other},
+	}
+
+	for _, tt := range tests {
+		if allowed := tt.rules.Allowed(tt.player); allowed != tt.allowed {
+			t.Fatalf("%s: expecting allowed to be %v; got %v", tt.name, tt.allowed, allowed)
+		}
+	}
+}
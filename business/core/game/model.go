@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/ardanlabs/liarsdice/business/core/game/fair"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
 )
@@ -15,26 +16,55 @@ const (
 	StatusRoundOver  = "roundover"
 	StatusGameOver   = "gameover"
 	StatusReconciled = "reconciled"
+	StatusAbandoned  = "abandoned"
 )
 
 // minNumberPlayers represents the minimum number of players required
 // to play a game.
 const minNumberPlayers = 2
 
+// MaxNumberPlayers represents the maximum number of players that can sit at
+// a table.
+const MaxNumberPlayers = 5
+
+// maxOuts represents the number of outs that removes a player from the game
+// when players are eliminated by outs.
+const maxOuts = 3
+
+// numberOfDice represents the number of dice a player starts the game with.
+const numberOfDice = 5
+
+// NumberOfSuits represents the number of faces on a die, so bets are made on
+// suits 1 through 6.
+const NumberOfSuits = 6
+
+// maxClientSeedLength represents the maximum length of a seed a player can
+// provide to mix into their rolls.
+const maxClientSeedLength = 64
+
 // State represents a copy of the game state.
 type State struct {
-	GameID          uuid.UUID
-	GameName        string
-	DateCreated     time.Time
-	Round           int
-	Status          string
-	PlayerLastOut   common.Address
-	PlayerLastWin   common.Address
-	PlayerTurn      common.Address
-	ExistingPlayers []common.Address
-	Cups            map[common.Address]Cup
-	Bets            []Bet
-	Balances        []BalanceFmt
+	GameID             uuid.UUID
+	Sequence           int // The sequence of the last event applied to the game.
+	GameName           string
+	DateCreated        time.Time
+	AnteUSD            float64
+	Round              int
+	Status             string
+	PlayerLastOut      common.Address
+	PlayerLastWin      common.Address
+	PlayerTurn         common.Address
+	ExistingPlayers    []common.Address
+	Cups               map[common.Address]Cup
+	Bets               []Bet
+	Balances           []BalanceFmt
+	Rules              Rules
+	Palifico           bool
+	Reveal             Reveal
+	TurnDeadline       time.Time
+	ServerSeed         string
+	ServerSeedHash     string
+	NextServerSeedHash string
 }
 
 // Bet represents a bet of dice made by a player.
@@ -46,10 +76,33 @@ type Bet struct {
 
 // Cup represents an individual cup being held by a player.
 type Cup struct {
-	Player   common.Address
-	OrderIdx int
-	Outs     int
-	Dice     []int
+	Player     common.Address
+	OrderIdx   int
+	Outs       int
+	Dice       []int
+	ClientSeed string
+	Commitment string
+}
+
+// Reveal represents the cups of every player and the outcome of the last
+// round that ended, so players can see why the round was won or lost.
+type Reveal struct {
+	Round  int
+	Cups   []Cup // The cups of the players who rolled in player order.
+	Bet    Bet   // The bet that was challenged.
+	Total  int   // The number of dice counted for the suit of the bet.
+	Exact  bool  // The bet was challenged with a spot on call.
+	Winner common.Address
+	Loser  common.Address
+}
+
+// Proof represents the revealed information for a round that allows the
+// dice to be verified.
+type Proof struct {
+	Round          int
+	ServerSeed     string
+	ServerSeedHash string
+	Cups           []fair.Cup
 }
 
 // Balance represents an individual balance for a player.
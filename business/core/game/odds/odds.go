@@ -0,0 +1,79 @@
+// Package odds provides support for calculating the probability that a bet
+// is true from the point of view of a single player.
+package odds
+
+import "math"
+
+// Odds represents the probability of a bet being true for a player who can
+// only see the dice in their own cup.
+type Odds struct {
+	Number   int
+	Suit     int
+	Known    int     // The dice in the player's cup that count towards the suit.
+	Unknown  int     // The dice in play the player can't see.
+	Expected float64 // The number of dice expected to count towards the suit.
+	AtLeast  float64 // The probability at least the number of dice count.
+	Exactly  float64 // The probability exactly the number of dice count.
+}
+
+// Calculate returns the odds of the bet for the player holding the specified
+// dice. The dice in play include the player's own dice. When wilds is true,
+// ones count towards every suit.
+func Calculate(dice []int, inPlay int, number int, suit int, wilds bool) Odds {
+	known := Count(dice, suit, wilds)
+	unknown := max(inPlay-len(dice), 0)
+	p := Chance(suit, wilds)
+
+	o := Odds{
+		Number:   number,
+		Suit:     suit,
+		Known:    known,
+		Unknown:  unknown,
+		Expected: float64(known) + float64(unknown)*p,
+	}
+
+	for k := max(number-known, 0); k <= unknown; k++ {
+		o.AtLeast += binomial(unknown, k, p)
+	}
+
+	if k := number - known; k >= 0 && k <= unknown {
+		o.Exactly = binomial(unknown, k, p)
+	}
+
+	return o
+}
+
+// Count returns the number of dice that count towards the suit. When wilds
+// is true, ones count towards every suit.
+func Count(dice []int, suit int, wilds bool) int {
+	var total int
+	for _, die := range dice {
+		switch {
+		case die == suit:
+			total++
+		case wilds && die == 1:
+			total++
+		}
+	}
+
+	return total
+}
+
+// Chance returns the probability a single die counts towards the suit.
+func Chance(suit int, wilds bool) float64 {
+	if wilds && suit != 1 {
+		return 2.0 / 6.0
+	}
+
+	return 1.0 / 6.0
+}
+
+// binomial returns the probability of k successes in n trials.
+func binomial(n int, k int, p float64) float64 {
+	coefficient := 1.0
+	for i := 1; i <= k; i++ {
+		coefficient = coefficient * float64(n-k+i) / float64(i)
+	}
+
+	return coefficient * math.Pow(p, float64(k)) * math.Pow(1-p, float64(n-k))
+}
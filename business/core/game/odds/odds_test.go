@@ -0,0 +1,61 @@
+package odds_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ardanlabs/liarsdice/business/core/game/odds"
+)
+
+func Test_Calculate(t *testing.T) {
+	dice := []int{4, 4, 1, 2, 6}
+
+	// A bet the player can see in their own cup is certain.
+	o := odds.Calculate(dice, 10, 2, 4, false)
+	if o.Known != 2 || o.Unknown != 5 {
+		t.Fatalf("expecting 2 known and 5 unknown dice; got %d and %d", o.Known, o.Unknown)
+	}
+
+	if !equal(o.AtLeast, 1) {
+		t.Fatalf("expecting a certain bet; got %f", o.AtLeast)
+	}
+
+	if want := math.Pow(5.0/6.0, 5); !equal(o.Exactly, want) {
+		t.Fatalf("expecting exactly %f; got %f", want, o.Exactly)
+	}
+
+	// With wilds the one in the cup counts and the other dice are twice as
+	// likely to count.
+	o = odds.Calculate(dice, 10, 4, 4, true)
+	if o.Known != 3 {
+		t.Fatalf("expecting the wild one to be counted; got %d", o.Known)
+	}
+
+	if want := 1 - math.Pow(4.0/6.0, 5); !equal(o.AtLeast, want) {
+		t.Fatalf("expecting at least %f; got %f", want, o.AtLeast)
+	}
+
+	if want := 3 + 5*(2.0/6.0); !equal(o.Expected, want) {
+		t.Fatalf("expecting %f dice; got %f", want, o.Expected)
+	}
+
+	// A bet on more dice than are in play is impossible.
+	o = odds.Calculate(dice, 10, 11, 4, true)
+	if o.AtLeast != 0 || o.Exactly != 0 {
+		t.Fatalf("expecting an impossible bet; got %+v", o)
+	}
+
+	// The probabilities of every exact count must add up to one.
+	var total float64
+	for number := 0; number <= 10; number++ {
+		total += odds.Calculate(dice, 10, number, 6, true).Exactly
+	}
+
+	if !equal(total, 1) {
+		t.Fatalf("expecting the exact probabilities to add up to 1; got %f", total)
+	}
+}
+
+func equal(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Represents the reasons a game is evicted from the tables.
+const (
+	EvictReconciled = "reconciled"
+	EvictAbandoned  = "abandoned"
+)
+
+// Eviction represents a game that was removed from the tables.
+type Eviction struct {
+	GameID uuid.UUID
+	Status string
+	Reason string
+}
+
+// Reaper removes the games that are no longer being played from the tables.
+type Reaper struct {
+	log           *logger.Logger
+	interval      time.Duration
+	grace         time.Duration
+	idleTTL       time.Duration
+	bankTimeout   time.Duration
+	autoReconcile bool
+	shutdown      chan struct{}
+	wg            sync.WaitGroup
+}
+
+// NewReaper constructs a reaper that checks the tables on the specified
+// interval. Reconciled games are evicted once the grace period has passed
+// and games without any player activity are abandoned after the idle TTL.
+// Games that are over but were never reconciled are abandoned after the
+// idle TTL so the antes are no longer reserved. With auto reconcile, they
+// are reconciled instead so the winner is paid.
+func NewReaper(log *logger.Logger, interval time.Duration, grace time.Duration, idleTTL time.Duration, bankTimeout time.Duration, autoReconcile bool) *Reaper {
+	return &Reaper{
+		log:           log,
+		interval:      interval,
+		grace:         grace,
+		idleTTL:       idleTTL,
+		bankTimeout:   bankTimeout,
+		autoReconcile: autoReconcile,
+		shutdown:      make(chan struct{}),
+	}
+}
+
+// Start begins checking the tables in a goroutine. The function is called
+// for every game that is evicted.
+func (r *Reaper) Start(fn func(ctx context.Context, e Eviction)) {
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.check(fn, time.Now())
+
+			case <-r.shutdown:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the reaper and waits for the goroutine to terminate.
+func (r *Reaper) Shutdown() {
+	close(r.shutdown)
+	r.wg.Wait()
+}
+
+// check evicts the reconciled and abandoned games from the tables.
+func (r *Reaper) check(fn func(ctx context.Context, e Eviction), now time.Time) {
+	ctx := context.Background()
+
+	for _, g := range Tables.all() {
+		status, lastActivity := g.activity()
+
+		var reason string
+
+		switch status {
+		case StatusReconciled, StatusAbandoned:
+			if now.Sub(lastActivity) < r.grace {
+				continue
+			}
+			reason = EvictReconciled
+			if status == StatusAbandoned {
+				reason = EvictAbandoned
+			}
+
+		case StatusGameOver:
+			if now.Sub(lastActivity) < r.idleTTL {
+				continue
+			}
+
+			if r.autoReconcile {
+				status, reason = r.reconcile(ctx, g)
+				break
+			}
+
+			if err := g.Abandon(ctx); err != nil {
+				r.log.Error(ctx, "reaper.abandon", "id", g.ID(), "ERROR", err)
+				continue
+			}
+			status = StatusAbandoned
+			reason = EvictAbandoned
+
+		case StatusNewGame, StatusPlaying, StatusRoundOver:
+			if now.Sub(lastActivity) < r.idleTTL {
+				continue
+			}
+
+			// Flag the game as abandoned so it's not resumed when the
+			// engine restarts.
+			if err := g.Abandon(ctx); err != nil {
+				r.log.Error(ctx, "reaper.abandon", "id", g.ID(), "ERROR", err)
+				continue
+			}
+			status = StatusAbandoned
+			reason = EvictAbandoned
+
+		default:
+			continue
+		}
+
+		Tables.evict(g.ID())
+
+		r.log.Info(ctx, "reaper.evict", "id", g.ID(), "status", status, "reason", reason, "lastActivity", lastActivity)
+
+		fn(ctx, Eviction{
+			GameID: g.ID(),
+			Status: status,
+			Reason: reason,
+		})
+	}
+}
+
+// reconcile pays the winner of a game that is over but was never reconciled.
+// If the game can't be reconciled, it's abandoned so the antes reserved for
+// the game are released.
+func (r *Reaper) reconcile(ctx context.Context, g *Game) (string, string) {
+	ctx, cancel := context.WithTimeout(ctx, r.bankTimeout)
+	defer cancel()
+
+	tx, _, err := g.Reconcile(ctx)
+	if err == nil {
+
+		// Tournament games are settled by the tournament, not the bank.
+		var hash string
+		if tx != nil {
+			hash = tx.Hash().Hex()
+		}
+
+		r.log.Info(ctx, "reaper.reconcile", "id", g.ID(), "winner", g.State().PlayerLastWin, "tx", hash)
+
+		return StatusReconciled, EvictReconciled
+	}
+
+	r.log.Error(ctx, "reaper.reconcile", "id", g.ID(), "ERROR", err)
+
+	if err := g.Abandon(ctx); err != nil {
+		r.log.Error(ctx, "reaper.abandon", "id", g.ID(), "ERROR", err)
+	}
+
+	return StatusAbandoned, EvictAbandoned
+}
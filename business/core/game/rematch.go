@@ -0,0 +1,181 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// Rematch represents the answers the players of a reconciled game have given
+// to playing the game again.
+type Rematch struct {
+	GameID    uuid.UUID        // The game the rematch is for.
+	NewGameID uuid.UUID        // The game created once the rematch started.
+	Accepted  []common.Address // The players who accepted, in seating order.
+	Declined  []common.Address // The players who declined.
+	Pending   []common.Address // The players who haven't answered.
+}
+
+// Rematch returns the answers the players have given to a rematch.
+func (g *Game) Rematch() Rematch {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.rematchAnswers()
+}
+
+// AnswerRematch records if the player accepts playing the game again. A
+// player can change their answer until the rematch has started.
+func (g *Game) AnswerRematch(ctx context.Context, player common.Address, accept bool) (Rematch, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.status != StatusReconciled {
+		return Rematch{}, fmt.Errorf("game status is required to be reconciled: status[%s]", g.status)
+	}
+
+	if g.rules.Tournament() {
+		return Rematch{}, fmt.Errorf("tournament games can't be rematched: tournament[%s]", g.rules.TournamentID)
+	}
+
+	if _, exists := g.cups[player]; !exists {
+		return Rematch{}, fmt.Errorf("player [%s] does not exist in the game", player)
+	}
+
+	if g.rematchID != uuid.Nil {
+		return Rematch{}, fmt.Errorf("rematch already started: game[%s]", g.rematchID)
+	}
+
+	if g.rematch == nil {
+		g.rematch = make(map[common.Address]bool)
+	}
+	g.rematch[player] = accept
+
+	g.log.Info(ctx, "game.answerrematch", "id", g.id, "player", player, "accept", accept)
+
+	return g.rematchAnswers(), nil
+}
+
+// StartRematch creates and starts a new game with the players who accepted
+// the rematch. The players keep their seating order and the game is played
+// with the same ante and rules.
+func (g *Game) StartRematch(ctx context.Context) (*Game, error) {
+
+	// The answers are captured under the lock, but the new game is created
+	// without it since that takes the tables lock and calls the bank.
+	g.mu.RLock()
+	rm := g.rematchAnswers()
+	rules := g.rules
+	anteUSD := g.anteUSD
+	g.mu.RUnlock()
+
+	if rm.NewGameID != uuid.Nil {
+		return nil, fmt.Errorf("rematch already started: game[%s]", rm.NewGameID)
+	}
+
+	if len(rm.Pending) > 0 {
+		return nil, fmt.Errorf("players have not answered the rematch: pending[%d]", len(rm.Pending))
+	}
+
+	if len(rm.Accepted) < rules.MinPlayers {
+		return nil, fmt.Errorf("not enough players accepted the rematch: accepted[%d] min[%d]", len(rm.Accepted), rules.MinPlayers)
+	}
+
+	ng, err := New(ctx, g.log, g.converter, g.storer, g.banker, g.dicer, rm.Accepted[0], anteUSD, rules)
+	if err != nil {
+		return nil, fmt.Errorf("new game: %w", err)
+	}
+
+	start := func() error {
+		for _, player := range rm.Accepted[1:] {
+			if err := ng.AddAccount(ctx, player); err != nil {
+				return err
+			}
+		}
+
+		return ng.StartGame(ctx)
+	}
+
+	if err := start(); err != nil {
+		if err := ng.Abandon(ctx); err != nil {
+			g.log.Error(ctx, "game.startrematch.abandon", "id", ng.ID(), "ERROR", err)
+		}
+
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Another call could have started the rematch in the meantime.
+	if g.rematchID != uuid.Nil {
+		if err := ng.Abandon(ctx); err != nil {
+			g.log.Error(ctx, "game.startrematch.abandon", "id", ng.ID(), "ERROR", err)
+		}
+
+		return nil, fmt.Errorf("rematch already started: game[%s]", g.rematchID)
+	}
+
+	g.rematchID = ng.ID()
+
+	g.log.Info(ctx, "game.startrematch", "id", g.id, "rematch", g.rematchID, "players", len(rm.Accepted))
+
+	return ng, nil
+}
+
+// ExpireRematch declines the rematch for the players who haven't answered
+// once the deadline has passed. It only applies when a player has answered,
+// so the rematch can be started with the players who accepted.
+func (g *Game) ExpireRematch(ctx context.Context, now time.Time) (Rematch, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.status != StatusReconciled || g.rematchID != uuid.Nil || g.rematchDeadline.IsZero() || now.Before(g.rematchDeadline) {
+		return Rematch{}, false
+	}
+
+	// The deadline only expires once.
+	g.rematchDeadline = time.Time{}
+
+	if len(g.rematch) == 0 {
+		return Rematch{}, false
+	}
+
+	rm := g.rematchAnswers()
+	if len(rm.Pending) == 0 {
+		return Rematch{}, false
+	}
+
+	for _, player := range rm.Pending {
+		g.rematch[player] = false
+	}
+
+	g.log.Info(ctx, "game.expirerematch", "id", g.id, "declined", len(rm.Pending))
+
+	return g.rematchAnswers(), true
+}
+
+// rematchAnswers groups the players by their answer to the rematch.
+func (g *Game) rematchAnswers() Rematch {
+	rm := Rematch{
+		GameID:    g.id,
+		NewGameID: g.rematchID,
+	}
+
+	for _, player := range g.players {
+		accept, answered := g.rematch[player]
+		switch {
+		case !answered:
+			rm.Pending = append(rm.Pending, player)
+		case accept:
+			rm.Accepted = append(rm.Accepted, player)
+		default:
+			rm.Declined = append(rm.Declined, player)
+		}
+	}
+
+	return rm
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// ReplayVersion represents the version of the replay document. It changes
+// when the document changes in a way that breaks existing viewers.
+const ReplayVersion = 1
+
+// Replay represents the play of a finished game round by round, so the game
+// can be played back.
+type Replay struct {
+	Version     int
+	GameID      uuid.UUID
+	DateCreated time.Time
+	AnteUSD     float64
+	Rules       Rules
+	Status      string
+	Players     []common.Address
+	Winner      common.Address
+	Rounds      []ReplayRound
+}
+
+// ReplayRound represents the play of a single round.
+type ReplayRound struct {
+	Round          int
+	Palifico       bool
+	Cups           []Cup          // The cups of the players who rolled, in roll order.
+	Bets           []Bet          // The bets in the order they were made.
+	Result         string         // The type of event that ended the round.
+	Total          int            // The number of dice counted for the suit of the last bet.
+	Winner         common.Address // The winner of the round.
+	Loser          common.Address // The loser of the round.
+	ServerSeed     string         // The server seed revealed when the round was called.
+	ServerSeedHash string
+}
+
+// NewReplay builds the replay of a finished game from its events. Rounds end
+// when a bet is called, a turn is forfeited or a player is put out of the
+// game. A round that was still being played when the game ended is left out.
+func NewReplay(events []Event) (Replay, error) {
+	var rounds []ReplayRound
+	var current *ReplayRound
+
+	end := func(result string, winner common.Address, loser common.Address) {
+		if current == nil {
+			return
+		}
+
+		current.Result = result
+		current.Winner = winner
+		current.Loser = loser
+		rounds = append(rounds, *current)
+		current = nil
+	}
+
+	state, err := replay(events, func(s *State, e Event) {
+		switch e.Type {
+		case EventStart, EventNextRound:
+			if s.Status != StatusPlaying {
+				break
+			}
+			current = &ReplayRound{
+				Round:          s.Round,
+				Palifico:       s.Palifico,
+				ServerSeedHash: s.ServerSeedHash,
+			}
+
+		case EventRoll:
+			if current == nil {
+				break
+			}
+			cup := s.Cups[e.Player]
+			cup.Dice = make([]int, len(e.Dice))
+			copy(cup.Dice, e.Dice)
+			current.Cups = append(current.Cups, cup)
+
+		case EventBet:
+			if current == nil {
+				break
+			}
+			current.Bets = append(current.Bets, Bet{
+				Player: e.Player,
+				Number: e.Number,
+				Suit:   e.Suit,
+			})
+
+		case EventLiar, EventExact, EventForfeit:
+			if current == nil {
+				break
+			}
+			current.Total = s.Reveal.Total
+			current.ServerSeed = e.ServerSeed
+			end(e.Type, e.Winner, e.Loser)
+
+		case EventOut, EventLeave:
+			if s.Status == StatusRoundOver || s.Status == StatusGameOver {
+				end(e.Type, e.Winner, e.Player)
+			}
+		}
+	})
+	if err != nil {
+		return Replay{}, fmt.Errorf("replay: %w", err)
+	}
+
+	switch state.Status {
+	case StatusGameOver, StatusReconciled, StatusAbandoned:
+	default:
+		return Replay{}, errors.New("game is not over")
+	}
+
+	// An abandoned game has no winner.
+	var winner common.Address
+	if state.Status != StatusAbandoned {
+		winner = state.PlayerLastWin
+	}
+
+	rp := Replay{
+		Version:     ReplayVersion,
+		GameID:      state.GameID,
+		DateCreated: state.DateCreated,
+		AnteUSD:     state.AnteUSD,
+		Rules:       state.Rules,
+		Status:      state.Status,
+		Players:     state.ExistingPlayers,
+		Winner:      winner,
+		Rounds:      rounds,
+	}
+
+	return rp, nil
+}
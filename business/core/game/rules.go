@@ -0,0 +1,306 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/core/game/odds"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// Represents the names of the supported rulesets.
+const (
+	RulesetClassic = "classic"
+	RulesetPerudo  = "perudo"
+)
+
+// Represents the different ways a player can be eliminated from a game.
+const (
+	EliminationOuts = "outs"
+	EliminationDice = "dice"
+)
+
+// Rules represents the rule variants a game is played with.
+type Rules struct {
+	Ruleset       string
+	MinPlayers    int              // The number of players required to start the game.
+	MaxPlayers    int              // The number of seats at the table.
+	Private       bool             // The table is hidden from the lobby and requires an invite.
+	Allowlist     []common.Address // The players who can join a private table without an invite code.
+	Elimination   string           // Players are eliminated by outs or by losing their dice.
+	WildOnes      bool             // Ones count towards every suit when the dice are counted.
+	SpotOn        bool             // Players can call the last bet as exactly right.
+	Palifico      bool             // A player reaching their last out starts a round without wilds.
+	TurnTimeout   time.Duration    // The time a player has to play. Zero means no limit.
+	TimeoutAction string           // The action taken when a player's turn expires.
+	TournamentID  uuid.UUID        // The tournament the game is a stage of. The tournament settles the funds.
+}
+
+// ParseRules returns the rules for the specified ruleset. An empty ruleset
+// returns the classic rules.
+func ParseRules(ruleset string) (Rules, error) {
+	switch ruleset {
+	case "", RulesetClassic:
+		return Rules{
+			Ruleset:     RulesetClassic,
+			MinPlayers:  minNumberPlayers,
+			MaxPlayers:  MaxNumberPlayers,
+			Elimination: EliminationOuts,
+		}, nil
+
+	case RulesetPerudo:
+		return Rules{
+			Ruleset:     RulesetPerudo,
+			MinPlayers:  minNumberPlayers,
+			MaxPlayers:  MaxNumberPlayers,
+			Elimination: EliminationDice,
+			WildOnes:    true,
+			SpotOn:      true,
+			Palifico:    true,
+		}, nil
+	}
+
+	return Rules{}, fmt.Errorf("unknown ruleset %q", ruleset)
+}
+
+// withSeats returns the rules with the default number of seats set when
+// the table didn't specify them.
+func (r Rules) withSeats() Rules {
+	if r.MinPlayers == 0 {
+		r.MinPlayers = minNumberPlayers
+	}
+
+	if r.MaxPlayers == 0 {
+		r.MaxPlayers = MaxNumberPlayers
+	}
+
+	return r
+}
+
+// Tournament reports if the game is a stage of a tournament.
+func (r Rules) Tournament() bool {
+	return r.TournamentID != uuid.Nil
+}
+
+// Allowed reports if the player can join the table without an invite code.
+func (r Rules) Allowed(player common.Address) bool {
+	if !r.Private {
+		return true
+	}
+
+	for _, allowed := range r.Allowlist {
+		if allowed == player {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validate checks the settings of the table are allowed.
+func (r Rules) validate() error {
+	if r.MinPlayers < minNumberPlayers {
+		return fmt.Errorf("min players must be at least %d: min[%d]", minNumberPlayers, r.MinPlayers)
+	}
+
+	if r.MaxPlayers > MaxNumberPlayers {
+		return fmt.Errorf("max players can't be more than %d: max[%d]", MaxNumberPlayers, r.MaxPlayers)
+	}
+
+	if r.MinPlayers > r.MaxPlayers {
+		return fmt.Errorf("min players can't be more than max players: min[%d] max[%d]", r.MinPlayers, r.MaxPlayers)
+	}
+
+	if r.TurnTimeout < 0 {
+		return fmt.Errorf("turn timeout can't be negative: timeout[%v]", r.TurnTimeout)
+	}
+
+	return nil
+}
+
+// MaxOuts returns the number of outs that removes a player from the game.
+// When players are eliminated by losing their dice, every out costs the
+// player one die.
+func (r Rules) MaxOuts() int {
+	if r.Elimination == EliminationDice {
+		return numberOfDice
+	}
+
+	return maxOuts
+}
+
+// dice returns the number of dice a player holds with the specified outs.
+// A player never holds less than no dice.
+func (r Rules) dice(outs int) int {
+	if r.Elimination == EliminationDice {
+		if outs >= numberOfDice {
+			return 0
+		}
+		return numberOfDice - outs
+	}
+
+	return numberOfDice
+}
+
+// wilds reports if ones are counted as wild for a round.
+func (r Rules) wilds(palifico bool) bool {
+	return r.WildOnes && !palifico
+}
+
+// validateBet checks the bet is allowed to follow the bets that were already
+// made in the round.
+func (r Rules) validateBet(bets []Bet, number int, suit int, palifico bool) error {
+	wilds := r.wilds(palifico)
+
+	// The opening bet can't be on the wild suit.
+	if len(bets) == 0 {
+		if wilds && suit == 1 {
+			return fmt.Errorf("the opening bet can't be on wild ones: suit[%d]", suit)
+		}
+		return nil
+	}
+
+	lastBet := bets[len(bets)-1]
+
+	// In a palifico round the suit of the opening bet can't be changed.
+	if palifico {
+		if suit != bets[0].Suit {
+			return fmt.Errorf("bet suit must stay the same in a palifico round: suit[%d] required[%d]", suit, bets[0].Suit)
+		}
+
+		if number <= lastBet.Number {
+			return fmt.Errorf("bet number must be greater than the last bet number: number[%d] last[%d]", number, lastBet.Number)
+		}
+
+		return nil
+	}
+
+	if wilds {
+		switch {
+
+		// Moving to ones requires at least half the number of dice.
+		case suit == 1 && lastBet.Suit != 1:
+			if minimum := (lastBet.Number + 1) / 2; number < minimum {
+				return fmt.Errorf("bet number on ones must be at least half the last bet number: number[%d] minimum[%d]", number, minimum)
+			}
+			return nil
+
+		// Moving off ones requires more than double the number of dice.
+		case suit != 1 && lastBet.Suit == 1:
+			if minimum := lastBet.Number*2 + 1; number < minimum {
+				return fmt.Errorf("bet number must be more than double the last bet number on ones: number[%d] minimum[%d]", number, minimum)
+			}
+			return nil
+
+		case suit == 1 && lastBet.Suit == 1:
+			if number <= lastBet.Number {
+				return fmt.Errorf("bet number must be greater than the last bet number: number[%d] last[%d]", number, lastBet.Number)
+			}
+			return nil
+		}
+	}
+
+	if number < lastBet.Number {
+		return fmt.Errorf("bet number must be greater or equal to the last bet number: number[%d] last[%d]", number, lastBet.Number)
+	}
+
+	if number == lastBet.Number && suit <= lastBet.Suit {
+		return fmt.Errorf("bet suit must be greater than the last bet suit: suit[%d] last[%d]", suit, lastBet.Suit)
+	}
+
+	return nil
+}
+
+// minimumBet returns the smallest bet that can follow the bets that were
+// already made in the round. If no bet is possible, false is returned.
+func (r Rules) minimumBet(bets []Bet, inPlay int, palifico bool) (number int, suit int, ok bool) {
+	wilds := r.wilds(palifico)
+
+	switch {
+	case len(bets) == 0:
+		number, suit = 1, 1
+		if wilds {
+			suit = 2
+		}
+
+	case palifico:
+		number, suit = bets[len(bets)-1].Number+1, bets[0].Suit
+
+	default:
+		lastBet := bets[len(bets)-1]
+
+		switch {
+		case wilds && lastBet.Suit == 1:
+			number, suit = lastBet.Number+1, 1
+
+		case lastBet.Suit < NumberOfSuits:
+			number, suit = lastBet.Number, lastBet.Suit+1
+
+		default:
+			number, suit = lastBet.Number+1, 1
+			if wilds {
+				suit = 2
+			}
+		}
+	}
+
+	if number > inPlay {
+		return 0, 0, false
+	}
+
+	if err := r.validateBet(bets, number, suit, palifico); err != nil {
+		return 0, 0, false
+	}
+
+	return number, suit, true
+}
+
+// count returns the number of dice in the cups that count towards the
+// specified suit.
+func (r Rules) count(cups map[common.Address]Cup, suit int, palifico bool) int {
+	wilds := r.wilds(palifico)
+
+	var total int
+	for _, cup := range cups {
+		total += odds.Count(cup.Dice, suit, wilds)
+	}
+
+	return total
+}
+
+// DiceInPlay returns the total number of dice held by the players who are
+// still in the game.
+func (s State) DiceInPlay() int {
+	var total int
+	for _, cup := range s.Cups {
+		if cup.Outs < s.Rules.MaxOuts() {
+			total += len(cup.Dice)
+		}
+	}
+
+	return total
+}
+
+// Wilds reports if ones are counted as wild for the current round.
+func (s State) Wilds() bool {
+	return s.Rules.wilds(s.Palifico)
+}
+
+// Odds returns the odds of the bet being true for the player based on the
+// dice in the player's cup.
+func (s State) Odds(player common.Address, number int, suit int) odds.Odds {
+	return odds.Calculate(s.Cups[player].Dice, s.DiceInPlay(), number, suit, s.Wilds())
+}
+
+// MinimumBet returns the smallest bet that can be made next in the current
+// round. If no bet is possible, false is returned.
+func (s State) MinimumBet() (number int, suit int, ok bool) {
+	return s.Rules.minimumBet(s.Bets, s.DiceInPlay(), s.Palifico)
+}
+
+// CheckBet checks the bet can be made next in the current round. The
+// problems are returned as field errors.
+func (s State) CheckBet(number int, suit int) error {
+	return s.Rules.checkBet(s.Bets, number, suit, s.DiceInPlay(), s.Palifico)
+}
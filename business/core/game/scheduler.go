@@ -0,0 +1,120 @@
+package game
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// Represents the actions the engine can take when a player's turn expires.
+const (
+	TimeoutPass = "pass"
+	TimeoutBid  = "bid"
+	TimeoutOut  = "out"
+)
+
+// TimeoutRematch is the action applied when the players of a reconciled game
+// don't answer a rematch in time. The players who haven't answered decline.
+const TimeoutRematch = "rematch"
+
+// Timeout represents a turn that expired and the action that was applied.
+// The player isn't set when the rematch expired.
+type Timeout struct {
+	GameID uuid.UUID
+	Player common.Address
+	Action string
+}
+
+// Scheduler enforces the turn deadlines for the games being played in the
+// tables.
+type Scheduler struct {
+	log         *logger.Logger
+	interval    time.Duration
+	bankTimeout time.Duration
+	shutdown    chan struct{}
+	wg          sync.WaitGroup
+}
+
+// NewScheduler constructs a scheduler that checks the turn deadlines on the
+// specified interval.
+func NewScheduler(log *logger.Logger, interval time.Duration, bankTimeout time.Duration) *Scheduler {
+	return &Scheduler{
+		log:         log,
+		interval:    interval,
+		bankTimeout: bankTimeout,
+		shutdown:    make(chan struct{}),
+	}
+}
+
+// Start begins checking the turn deadlines in a goroutine. The function is
+// called for every timeout that is applied to a game.
+func (s *Scheduler) Start(fn func(ctx context.Context, t Timeout)) {
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.check(fn)
+
+			case <-s.shutdown:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the scheduler and waits for the goroutine to terminate.
+func (s *Scheduler) Shutdown() {
+	close(s.shutdown)
+	s.wg.Wait()
+}
+
+// check applies the timeout action to every game where the current turn
+// or the rematch has expired.
+func (s *Scheduler) check(fn func(ctx context.Context, t Timeout)) {
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, g := range Tables.all() {
+		if _, expired := g.ExpireRematch(ctx, now); expired {
+			s.log.Info(ctx, "scheduler.timeout", "id", g.ID(), "action", TimeoutRematch)
+			s.apply(fn, Timeout{GameID: g.ID(), Action: TimeoutRematch})
+			continue
+		}
+
+		t, expired := g.ExpireTurn(ctx, now)
+		if !expired {
+			continue
+		}
+
+		s.log.Info(ctx, "scheduler.timeout", "id", t.GameID, "player", t.Player, "action", t.Action)
+
+		// An out ends the round, so the next round needs to be started.
+		if t.Action == TimeoutOut {
+			if _, err := g.NextRound(ctx); err != nil {
+				s.log.Error(ctx, "scheduler.nextround", "id", t.GameID, "ERROR", err)
+			}
+		}
+
+		s.apply(fn, t)
+	}
+}
+
+// apply calls the function for a timeout with the bank timeout, since the
+// function settles the games that are over and starts the rematches.
+func (s *Scheduler) apply(fn func(ctx context.Context, t Timeout), t Timeout) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.bankTimeout)
+	defer cancel()
+
+	fn(ctx, t)
+}
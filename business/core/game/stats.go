@@ -0,0 +1,54 @@
+package game
+
+import (
+	"github.com/ardanlabs/liarsdice/business/core/game/odds"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Stats represents a player's record over the games that have been stored.
+type Stats struct {
+	Player     common.Address
+	Games      int // The games played to the end.
+	Wins       int // The games won.
+	Calls      int // The liar and exact calls made.
+	CallsRight int // The calls that won the round.
+	Bets       int // The bets made in rounds that were revealed.
+	Bluffs     int // The bets that were false once the dice were revealed.
+}
+
+// WinRate returns the share of the games the player won.
+func (s Stats) WinRate() float64 {
+	return rate(s.Wins, s.Games)
+}
+
+// CallRate returns the share of the calls the player was right about.
+func (s Stats) CallRate() float64 {
+	return rate(s.CallsRight, s.Calls)
+}
+
+// BluffRate returns the share of the bets the player made that were false.
+func (s Stats) BluffRate() float64 {
+	return rate(s.Bluffs, s.Bets)
+}
+
+// rate returns n as a share of total, or zero when there is no total.
+func rate(n int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(n) / float64(total)
+}
+
+// Bluff reports if the bet was false once the dice rolled for the round
+// were revealed.
+func (r Rules) Bluff(bet Bet, cups []Cup, palifico bool) bool {
+	wilds := r.wilds(palifico)
+
+	var total int
+	for _, cup := range cups {
+		total += odds.Count(cup.Dice, bet.Suit, wilds)
+	}
+
+	return total < bet.Number
+}
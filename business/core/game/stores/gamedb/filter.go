@@ -0,0 +1,48 @@
+package gamedb
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// applyFilter adds the where clause for the filter and cursor to the query
+// of games.
+func applyFilter(filter game.QueryFilter, after game.Cursor, data map[string]any, buf *bytes.Buffer) {
+	var empty common.Address
+	var wc []string
+
+	if filter.Player != empty {
+		data["player"] = filter.Player.String()
+		wc = append(wc, ":player = ANY(s.players)")
+	}
+
+	if filter.Status != "" {
+		data["status"] = filter.Status
+		wc = append(wc, "s.status = :status")
+	}
+
+	if !filter.StartDate.IsZero() {
+		data["start_date"] = filter.StartDate.UTC()
+		wc = append(wc, "g.date_created >= :start_date")
+	}
+
+	if !filter.EndDate.IsZero() {
+		data["end_date"] = filter.EndDate.UTC()
+		wc = append(wc, "g.date_created < :end_date")
+	}
+
+	data["viewer"] = filter.Viewer.String()
+	wc = append(wc, "(NOT g.private OR :viewer = ANY(s.players))")
+
+	if !after.IsZero() {
+		data["cursor_date"] = after.DateCreated.UTC()
+		data["cursor_id"] = after.GameID.String()
+		wc = append(wc, "(g.date_created, g.game_id) < (:cursor_date, :cursor_id)")
+	}
+
+	buf.WriteString("\n\tWHERE\n\t\t")
+	buf.WriteString(strings.Join(wc, " AND\n\t\t"))
+}
@@ -2,6 +2,7 @@
 package gamedb
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"github.com/ardanlabs/liarsdice/business/core/game"
 	"github.com/ardanlabs/liarsdice/business/data/sqldb"
 	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
@@ -33,21 +35,35 @@ func (s *Store) Create(ctx context.Context, g *game.Game) error {
 
 	// NEED A TRANSACTION HERE
 
+	state := g.State()
+
 	d := struct {
 		ID          uuid.UUID `db:"game_id"`
 		Name        string    `db:"name"`
 		DateCreated time.Time `db:"date_created"`
+		AnteUSD     float64   `db:"ante_usd"`
+		MinPlayers  int       `db:"min_players"`
+		MaxPlayers  int       `db:"max_players"`
+		Ruleset     string    `db:"ruleset"`
+		TurnTimeout int       `db:"turn_timeout_seconds"`
+		Private     bool      `db:"private"`
 	}{
 		ID:          g.ID(),
 		Name:        g.ID().String(),
 		DateCreated: g.DateCreated(),
+		AnteUSD:     state.AnteUSD,
+		MinPlayers:  state.Rules.MinPlayers,
+		MaxPlayers:  state.Rules.MaxPlayers,
+		Ruleset:     state.Rules.Ruleset,
+		TurnTimeout: int(state.Rules.TurnTimeout.Seconds()),
+		Private:     state.Rules.Private,
 	}
 
 	q := `
     INSERT INTO games
-        (game_id, name, date_created)
+        (game_id, name, date_created, ante_usd, min_players, max_players, ruleset, turn_timeout_seconds, private)
     VALUES
-        (:game_id, :name, :date_created)`
+        (:game_id, :name, :date_created, :ante_usd, :min_players, :max_players, :ruleset, :turn_timeout_seconds, :private)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, d); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -55,14 +71,20 @@ func (s *Store) Create(ctx context.Context, g *game.Game) error {
 
 	q = `
     INSERT INTO game_state
-        (game_id, round, status, player_last_out, player_last_win, player_turn, existing_players)
+        (game_id, round, status, player_last_out, player_last_win, player_turn, existing_players, server_seed, server_seed_hash)
     VALUES
-		(:game_id, :round, :status, :player_last_out, :player_last_win, :player_turn, :existing_players)`
+		(:game_id, :round, :status, :player_last_out, :player_last_win, :player_turn, :existing_players, :server_seed, :server_seed_hash)`
 
-	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBState(g.State())); err != nil {
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBState(state)); err != nil {
 		return fmt.Errorf("namedexeccontext-state: %w", err)
 	}
 
+	for _, e := range g.Events() {
+		if err := s.InsertEvent(ctx, e); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -74,9 +96,9 @@ func (s *Store) InsertRound(ctx context.Context, state game.State) error {
 
 	q := `
     INSERT INTO game_state
-        (game_id, round, status, player_last_out, player_last_win, player_turn, existing_players)
+        (game_id, round, status, player_last_out, player_last_win, player_turn, existing_players, server_seed, server_seed_hash)
     VALUES
-		(:game_id, :round, :status, :player_last_out, :player_last_win, :player_turn, :existing_players)`
+		(:game_id, :round, :status, :player_last_out, :player_last_win, :player_turn, :existing_players, :server_seed, :server_seed_hash)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, dbState); err != nil {
 		return fmt.Errorf("namedexeccontext-state: %w", err)
@@ -84,9 +106,9 @@ func (s *Store) InsertRound(ctx context.Context, state game.State) error {
 
 	q = `
     INSERT INTO game_cups
-        (game_id, round, player, order_idx, outs, dice)
+        (game_id, round, player, order_idx, outs, dice, client_seed, commitment)
     VALUES
-		(:game_id, :round, :player, :order_idx, :outs, :dice)`
+		(:game_id, :round, :player, :order_idx, :outs, :dice, :client_seed, :commitment)`
 
 	for _, dbCup := range dbState.Cups {
 		if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, dbCup); err != nil {
@@ -141,7 +163,9 @@ func (s *Store) QueryStateByID(ctx context.Context, gameID uuid.UUID, round int)
 		s.player_last_out,
 		s.player_last_win,
 		s.player_turn,
-		s.existing_players
+		s.existing_players,
+		s.server_seed,
+		s.server_seed_hash
 	FROM
 		games AS g
 	JOIN
@@ -165,7 +189,9 @@ func (s *Store) QueryStateByID(ctx context.Context, gameID uuid.UUID, round int)
 		player,
 		order_idx,
 		outs,
-		dice
+		dice,
+		client_seed,
+		commitment
 	FROM
 		game_cups
 	WHERE
@@ -221,3 +247,356 @@ func (s *Store) QueryStateByID(ctx context.Context, gameID uuid.UUID, round int)
 
 	return toCoreState(dbState)
 }
+
+// InsertEvent adds an event to the game's event log in the db.
+// The secret server seeds of the event are stored apart from the event, so
+// readers of the event log can't learn them.
+func (s *Store) InsertEvent(ctx context.Context, e game.Event) error {
+	dbEvt, err := toDBEvent(e)
+	if err != nil {
+		return fmt.Errorf("todbevent: %w", err)
+	}
+
+	if seeds, exists := toDBServerSeeds(e); exists {
+		q := `
+    INSERT INTO game_server_seeds
+        (game_id, sequence, server_seed, next_server_seed)
+    VALUES
+		(:game_id, :sequence, :server_seed, :next_server_seed)`
+
+		if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, seeds); err != nil {
+			return fmt.Errorf("namedexeccontext-seeds: %w", err)
+		}
+	}
+
+	q := `
+    INSERT INTO game_events
+        (game_id, sequence, type, round, player, data, date_created)
+    VALUES
+		(:game_id, :sequence, :type, :round, :player, :data, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, dbEvt); err != nil {
+		return fmt.Errorf("namedexeccontext-event: %w", err)
+	}
+
+	return nil
+}
+
+// QueryEvents gets the events for the specified game from the database in
+// sequence order.
+func (s *Store) QueryEvents(ctx context.Context, gameID uuid.UUID) ([]game.Event, error) {
+	data := struct {
+		ID string `db:"game_id"`
+	}{
+		ID: gameID.String(),
+	}
+
+	q := `
+	SELECT
+		game_id,
+		sequence,
+		type,
+		round,
+		player,
+		data,
+		date_created
+	FROM
+		game_events
+	WHERE
+		game_id = :game_id
+	ORDER BY
+		sequence`
+
+	var dbEvts []dbEvent
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEvts); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return nil, fmt.Errorf("namedqueryslice: %w", game.ErrNotFound)
+		}
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	if len(dbEvts) == 0 {
+		return nil, fmt.Errorf("namedqueryslice: %w", game.ErrNotFound)
+	}
+
+	return toCoreEvents(dbEvts)
+}
+
+// QueryServerSeeds gets the secret server seeds recorded with the events of
+// the specified game. They are only used by the engine to resume a game.
+func (s *Store) QueryServerSeeds(ctx context.Context, gameID uuid.UUID) ([]game.ServerSeeds, error) {
+	data := struct {
+		ID string `db:"game_id"`
+	}{
+		ID: gameID.String(),
+	}
+
+	q := `
+	SELECT
+		game_id,
+		sequence,
+		server_seed,
+		next_server_seed
+	FROM
+		game_server_seeds
+	WHERE
+		game_id = :game_id
+	ORDER BY
+		sequence`
+
+	var dbSeeds []dbServerSeeds
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbSeeds); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreServerSeeds(dbSeeds), nil
+}
+
+// QueryUnreconciled gets the ids of the games that have not been reconciled
+// or abandoned.
+func (s *Store) QueryUnreconciled(ctx context.Context) ([]uuid.UUID, error) {
+	q := `
+	SELECT
+		game_id
+	FROM
+		game_events
+	GROUP BY
+		game_id
+	HAVING
+		bool_and(type NOT IN ('reconcile', 'abandon'))`
+
+	var dbGames []struct {
+		ID uuid.UUID `db:"game_id"`
+	}
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, struct{}{}, &dbGames); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	gameIDs := make([]uuid.UUID, len(dbGames))
+	for i, dbGame := range dbGames {
+		gameIDs[i] = dbGame.ID
+	}
+
+	return gameIDs, nil
+}
+
+// QueryStats gets the statistics of the specified player from the games and
+// rounds stored in the db.
+func (s *Store) QueryStats(ctx context.Context, player common.Address) (game.Stats, error) {
+	data := struct {
+		Player string `db:"player"`
+	}{
+		Player: player.String(),
+	}
+
+	q := `
+	SELECT
+		count(DISTINCT s.game_id) AS games,
+		count(DISTINCT s.game_id) FILTER (WHERE s.player_last_win = :player) AS wins
+	FROM
+		game_state AS s
+	JOIN
+		game_cups AS c ON c.game_id = s.game_id AND c.round = s.round
+	WHERE
+		s.status = 'reconciled' AND
+		c.player = :player`
+
+	var dbGames dbStatsGames
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbGames); err != nil {
+		return game.Stats{}, fmt.Errorf("namedquerystruct-games: %w", err)
+	}
+
+	q = `
+	SELECT
+		count(1) AS calls,
+		count(1) FILTER (WHERE data->>'winner' = :player) AS calls_right
+	FROM
+		game_events
+	WHERE
+		type IN ('liar', 'exact') AND
+		player = :player`
+
+	var dbCalls dbStatsCalls
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbCalls); err != nil {
+		return game.Stats{}, fmt.Errorf("namedquerystruct-calls: %w", err)
+	}
+
+	// Only the bets of rounds that ended with the dice revealed are counted.
+	q = `
+	SELECT
+		b.game_id,
+		b.round,
+		b.bet_order,
+		b.player,
+		b.number,
+		b.suit
+	FROM
+		game_bets AS b
+	JOIN
+		game_state AS s ON s.game_id = b.game_id AND s.round = b.round
+	WHERE
+		s.status = 'roundover' AND
+		b.player = :player`
+
+	var dbBets []dbBet
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbBets); err != nil {
+		return game.Stats{}, fmt.Errorf("namedqueryslice-bets: %w", err)
+	}
+
+	q = `
+	SELECT
+		c.game_id,
+		c.round,
+		c.player,
+		c.order_idx,
+		c.outs,
+		c.dice,
+		c.client_seed,
+		c.commitment
+	FROM
+		game_cups AS c
+	JOIN
+		game_state AS s ON s.game_id = c.game_id AND s.round = c.round
+	WHERE
+		s.status = 'roundover' AND
+		(c.game_id, c.round) IN (SELECT game_id, round FROM game_bets WHERE player = :player)`
+
+	var dbCups []dbCup
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbCups); err != nil {
+		return game.Stats{}, fmt.Errorf("namedqueryslice-cups: %w", err)
+	}
+
+	// The rules of the games and the palifico rounds decide how the dice
+	// are counted.
+	q = `
+	SELECT
+		game_id,
+		sequence,
+		type,
+		round,
+		player,
+		data,
+		date_created
+	FROM
+		game_events
+	WHERE
+		type IN ('new', 'nextround') AND
+		game_id IN (SELECT game_id FROM game_bets WHERE player = :player)`
+
+	var dbEvts []dbEvent
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEvts); err != nil {
+		return game.Stats{}, fmt.Errorf("namedqueryslice-events: %w", err)
+	}
+
+	evts, err := toCoreEvents(dbEvts)
+	if err != nil {
+		return game.Stats{}, fmt.Errorf("tocoreevents: %w", err)
+	}
+
+	return toCoreStats(player, dbGames, dbCalls, dbBets, dbCups, evts), nil
+}
+
+// QueryGames gets a page of the games that match the filter from the db,
+// from newest to oldest, starting after the cursor.
+func (s *Store) QueryGames(ctx context.Context, filter game.QueryFilter, after game.Cursor, limit int) ([]game.Summary, error) {
+	data := map[string]any{
+		"limit": limit,
+	}
+
+	// The status and players of a game are taken from its event log, which
+	// is written for every change to the game.
+	const q = `
+	WITH summaries AS (
+		SELECT
+			game_id,
+			max(round) AS round,
+			coalesce(array_agg(DISTINCT player) FILTER (WHERE type = 'join'), '{}') AS players,
+			coalesce(max(player) FILTER (WHERE type = 'reconcile'), '') AS winner,
+			CASE
+				WHEN bool_or(type = 'reconcile') THEN 'reconciled'
+				WHEN bool_or(type = 'abandon') THEN 'abandoned'
+				WHEN bool_or(data->>'status' = 'gameover') THEN 'gameover'
+				WHEN bool_or(type = 'start') THEN 'playing'
+				ELSE 'newgame'
+			END AS status
+		FROM
+			game_events
+		GROUP BY
+			game_id
+	)
+	SELECT
+		g.game_id,
+		g.date_created,
+		g.ante_usd,
+		g.ruleset,
+		g.private,
+		s.status,
+		s.round,
+		s.players,
+		s.winner
+	FROM
+		games AS g
+	JOIN
+		summaries AS s ON s.game_id = g.game_id`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, after, data, buf)
+
+	buf.WriteString(`
+	ORDER BY
+		g.date_created DESC,
+		g.game_id DESC
+	LIMIT :limit`)
+
+	var dbSums []dbSummary
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbSums); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreSummaries(dbSums), nil
+}
+
+// QueryRounds gets every round stored for the specified game from the db in
+// round order.
+func (s *Store) QueryRounds(ctx context.Context, gameID uuid.UUID) ([]game.State, error) {
+	data := struct {
+		ID string `db:"game_id"`
+	}{
+		ID: gameID.String(),
+	}
+
+	q := `
+	SELECT
+		round
+	FROM
+		game_state
+	WHERE
+		game_id = :game_id
+	ORDER BY
+		round`
+
+	var dbRounds []struct {
+		Round int `db:"round"`
+	}
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbRounds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	if len(dbRounds) == 0 {
+		return nil, fmt.Errorf("namedqueryslice: %w", game.ErrNotFound)
+	}
+
+	states := make([]game.State, len(dbRounds))
+	for i, dbRound := range dbRounds {
+		state, err := s.QueryStateByID(ctx, gameID, dbRound.Round)
+		if err != nil {
+			return nil, fmt.Errorf("query round[%d]: %w", dbRound.Round, err)
+		}
+		states[i] = state
+	}
+
+	return states, nil
+}
@@ -1,6 +1,7 @@
 package gamedb
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -20,18 +21,22 @@ type dbState struct {
 	PlayerLastWin   string         `db:"player_last_win"`
 	PlayerTurn      string         `db:"player_turn"`
 	ExistingPlayers dbarray.String `db:"existing_players"`
+	ServerSeed      string         `db:"server_seed"`
+	ServerSeedHash  string         `db:"server_seed_hash"`
 	Cups            []dbCup
 	Bets            []dbBet
 	Balances        []dbBalance
 }
 
 type dbCup struct {
-	ID       uuid.UUID     `db:"game_id"`
-	Round    int           `db:"round"`
-	Player   string        `db:"player"`
-	OrderIdx int           `db:"order_idx"`
-	Outs     int           `db:"outs"`
-	Dice     dbarray.Int64 `db:"dice"`
+	ID         uuid.UUID     `db:"game_id"`
+	Round      int           `db:"round"`
+	Player     string        `db:"player"`
+	OrderIdx   int           `db:"order_idx"`
+	Outs       int           `db:"outs"`
+	Dice       dbarray.Int64 `db:"dice"`
+	ClientSeed string        `db:"client_seed"`
+	Commitment string        `db:"commitment"`
 }
 
 type dbBet struct {
@@ -59,12 +64,14 @@ func toDBState(state game.State) dbState {
 		}
 
 		cups = append(cups, dbCup{
-			ID:       state.GameID,
-			Round:    state.Round,
-			Player:   cup.Player.String(),
-			OrderIdx: cup.OrderIdx,
-			Outs:     cup.Outs,
-			Dice:     dice,
+			ID:         state.GameID,
+			Round:      state.Round,
+			Player:     cup.Player.String(),
+			OrderIdx:   cup.OrderIdx,
+			Outs:       cup.Outs,
+			Dice:       dice,
+			ClientSeed: cup.ClientSeed,
+			Commitment: cup.Commitment,
 		})
 	}
 
@@ -105,6 +112,8 @@ func toDBState(state game.State) dbState {
 		PlayerLastWin:   state.PlayerLastWin.String(),
 		PlayerTurn:      state.PlayerTurn.String(),
 		ExistingPlayers: existingPlayers,
+		ServerSeed:      state.ServerSeed,
+		ServerSeedHash:  state.ServerSeedHash,
 		Cups:            cups,
 		Bets:            bets,
 		Balances:        balances,
@@ -139,10 +148,12 @@ func toCoreState(dbState dbState) (game.State, error) {
 		}
 
 		cups[player] = game.Cup{
-			Player:   player,
-			OrderIdx: cup.OrderIdx,
-			Outs:     cup.Outs,
-			Dice:     dice,
+			Player:     player,
+			OrderIdx:   cup.OrderIdx,
+			Outs:       cup.Outs,
+			Dice:       dice,
+			ClientSeed: cup.ClientSeed,
+			Commitment: cup.Commitment,
 		}
 	}
 
@@ -176,7 +187,398 @@ func toCoreState(dbState dbState) (game.State, error) {
 		Cups:            cups,
 		Bets:            bets,
 		Balances:        balances,
+		ServerSeed:      dbState.ServerSeed,
+		ServerSeedHash:  dbState.ServerSeedHash,
 	}
 
 	return state, nil
 }
+
+// =============================================================================
+
+type dbEvent struct {
+	ID          uuid.UUID `db:"game_id"`
+	Sequence    int       `db:"sequence"`
+	Type        string    `db:"type"`
+	Round       int       `db:"round"`
+	Player      string    `db:"player"`
+	Data        string    `db:"data"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+// dbEventData represents the values of an event that are stored as json.
+type dbEventData struct {
+	Timeout            bool             `json:"timeout,omitempty"`
+	Turn               string           `json:"turn,omitempty"`
+	Number             int              `json:"number,omitempty"`
+	Suit               int              `json:"suit,omitempty"`
+	Dice               []int            `json:"dice,omitempty"`
+	ClientSeed         string           `json:"clientSeed,omitempty"`
+	Commitment         string           `json:"commitment,omitempty"`
+	Outs               int              `json:"outs,omitempty"`
+	Winner             string           `json:"winner,omitempty"`
+	Loser              string           `json:"loser,omitempty"`
+	Status             string           `json:"status,omitempty"`
+	Palifico           bool             `json:"palifico,omitempty"`
+	AnteUSD            float64          `json:"anteUSD,omitempty"`
+	Rules              *dbEventRules    `json:"rules,omitempty"`
+	ServerSeed         string           `json:"serverSeed,omitempty"`
+	ServerSeedHash     string           `json:"serverSeedHash,omitempty"`
+	NextServerSeedHash string           `json:"nextServerSeedHash,omitempty"`
+	Balances           []dbEventBalance `json:"balances,omitempty"`
+}
+
+type dbEventRules struct {
+	Ruleset       string        `json:"ruleset"`
+	MinPlayers    int           `json:"minPlayers,omitempty"`
+	MaxPlayers    int           `json:"maxPlayers,omitempty"`
+	Private       bool          `json:"private,omitempty"`
+	Allowlist     []string      `json:"allowlist,omitempty"`
+	Elimination   string        `json:"elimination"`
+	WildOnes      bool          `json:"wildOnes"`
+	SpotOn        bool          `json:"spotOn"`
+	Palifico      bool          `json:"palifico"`
+	TurnTimeout   time.Duration `json:"turnTimeout"`
+	TimeoutAction string        `json:"timeoutAction"`
+	TournamentID  string        `json:"tournamentID,omitempty"`
+}
+
+type dbEventBalance struct {
+	Player string `json:"player"`
+	Amount string `json:"amount"`
+}
+
+func toDBEvent(e game.Event) (dbEvent, error) {
+	data := dbEventData{
+		Timeout:            e.Timeout,
+		Number:             e.Number,
+		Suit:               e.Suit,
+		Dice:               e.Dice,
+		ClientSeed:         e.ClientSeed,
+		Commitment:         e.Commitment,
+		Outs:               e.Outs,
+		Status:             e.Status,
+		Palifico:           e.Palifico,
+		AnteUSD:            e.AnteUSD,
+		ServerSeedHash:     e.ServerSeedHash,
+		NextServerSeedHash: e.NextServerSeedHash,
+	}
+
+	// The server seed is only stored with the event once it's been revealed.
+	// The secret seeds are stored apart from the events.
+	if e.Type != game.EventNew {
+		data.ServerSeed = e.ServerSeed
+	}
+
+	var empty common.Address
+	if e.Turn != empty {
+		data.Turn = e.Turn.String()
+	}
+	if e.Winner != empty {
+		data.Winner = e.Winner.String()
+	}
+	if e.Loser != empty {
+		data.Loser = e.Loser.String()
+	}
+
+	if e.Type == game.EventNew {
+		data.Rules = &dbEventRules{
+			Ruleset:       e.Rules.Ruleset,
+			MinPlayers:    e.Rules.MinPlayers,
+			MaxPlayers:    e.Rules.MaxPlayers,
+			Private:       e.Rules.Private,
+			Allowlist:     toDBAddresses(e.Rules.Allowlist),
+			Elimination:   e.Rules.Elimination,
+			WildOnes:      e.Rules.WildOnes,
+			SpotOn:        e.Rules.SpotOn,
+			Palifico:      e.Rules.Palifico,
+			TurnTimeout:   e.Rules.TurnTimeout,
+			TimeoutAction: e.Rules.TimeoutAction,
+			TournamentID:  toDBTournamentID(e.Rules.TournamentID),
+		}
+	}
+
+	for _, balance := range e.Balances {
+		data.Balances = append(data.Balances, dbEventBalance{
+			Player: balance.Player.String(),
+			Amount: balance.Amount,
+		})
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return dbEvent{}, fmt.Errorf("marshal: %w", err)
+	}
+
+	dbEvt := dbEvent{
+		ID:          e.GameID,
+		Sequence:    e.Sequence,
+		Type:        e.Type,
+		Round:       e.Round,
+		Player:      e.Player.String(),
+		Data:        string(b),
+		DateCreated: e.Date,
+	}
+
+	return dbEvt, nil
+}
+
+func toCoreEvent(dbEvt dbEvent) (game.Event, error) {
+	var data dbEventData
+	if err := json.Unmarshal([]byte(dbEvt.Data), &data); err != nil {
+		return game.Event{}, fmt.Errorf("unmarshal: sequence[%d]: %w", dbEvt.Sequence, err)
+	}
+
+	e := game.Event{
+		GameID:             dbEvt.ID,
+		Sequence:           dbEvt.Sequence,
+		Type:               dbEvt.Type,
+		Round:              dbEvt.Round,
+		Player:             common.HexToAddress(dbEvt.Player),
+		Date:               dbEvt.DateCreated,
+		Timeout:            data.Timeout,
+		Turn:               common.HexToAddress(data.Turn),
+		Number:             data.Number,
+		Suit:               data.Suit,
+		Dice:               data.Dice,
+		ClientSeed:         data.ClientSeed,
+		Commitment:         data.Commitment,
+		Outs:               data.Outs,
+		Winner:             common.HexToAddress(data.Winner),
+		Loser:              common.HexToAddress(data.Loser),
+		Status:             data.Status,
+		Palifico:           data.Palifico,
+		AnteUSD:            data.AnteUSD,
+		ServerSeed:         data.ServerSeed,
+		ServerSeedHash:     data.ServerSeedHash,
+		NextServerSeedHash: data.NextServerSeedHash,
+	}
+
+	if data.Rules != nil {
+		e.Rules = game.Rules{
+			Ruleset:       data.Rules.Ruleset,
+			MinPlayers:    data.Rules.MinPlayers,
+			MaxPlayers:    data.Rules.MaxPlayers,
+			Private:       data.Rules.Private,
+			Allowlist:     toCoreAddresses(data.Rules.Allowlist),
+			Elimination:   data.Rules.Elimination,
+			WildOnes:      data.Rules.WildOnes,
+			SpotOn:        data.Rules.SpotOn,
+			Palifico:      data.Rules.Palifico,
+			TurnTimeout:   data.Rules.TurnTimeout,
+			TimeoutAction: data.Rules.TimeoutAction,
+			TournamentID:  toCoreTournamentID(data.Rules.TournamentID),
+		}
+	}
+
+	for _, balance := range data.Balances {
+		e.Balances = append(e.Balances, game.BalanceFmt{
+			Player: common.HexToAddress(balance.Player),
+			Amount: balance.Amount,
+		})
+	}
+
+	return e, nil
+}
+
+// dbServerSeeds represents the secret server seeds recorded with an event.
+type dbServerSeeds struct {
+	ID             uuid.UUID `db:"game_id"`
+	Sequence       int       `db:"sequence"`
+	ServerSeed     string    `db:"server_seed"`
+	NextServerSeed string    `db:"next_server_seed"`
+}
+
+// toDBServerSeeds returns the secret server seeds of the event, if the event
+// has any.
+func toDBServerSeeds(e game.Event) (dbServerSeeds, bool) {
+	seeds := dbServerSeeds{
+		ID:             e.GameID,
+		Sequence:       e.Sequence,
+		NextServerSeed: e.NextServerSeed,
+	}
+
+	if e.Type == game.EventNew {
+		seeds.ServerSeed = e.ServerSeed
+	}
+
+	return seeds, seeds.ServerSeed != "" || seeds.NextServerSeed != ""
+}
+
+func toCoreServerSeeds(dbSeeds []dbServerSeeds) []game.ServerSeeds {
+	seeds := make([]game.ServerSeeds, len(dbSeeds))
+	for i, dbSeed := range dbSeeds {
+		seeds[i] = game.ServerSeeds{
+			Sequence:       dbSeed.Sequence,
+			ServerSeed:     dbSeed.ServerSeed,
+			NextServerSeed: dbSeed.NextServerSeed,
+		}
+	}
+
+	return seeds
+}
+
+func toCoreEvents(dbEvts []dbEvent) ([]game.Event, error) {
+	events := make([]game.Event, len(dbEvts))
+	for i, dbEvt := range dbEvts {
+		e, err := toCoreEvent(dbEvt)
+		if err != nil {
+			return nil, err
+		}
+		events[i] = e
+	}
+
+	return events, nil
+}
+
+func toDBAddresses(addresses []common.Address) []string {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	strs := make([]string, len(addresses))
+	for i, address := range addresses {
+		strs[i] = address.String()
+	}
+
+	return strs
+}
+
+func toDBTournamentID(tournamentID uuid.UUID) string {
+	if tournamentID == uuid.Nil {
+		return ""
+	}
+
+	return tournamentID.String()
+}
+
+func toCoreTournamentID(str string) uuid.UUID {
+	if str == "" {
+		return uuid.Nil
+	}
+
+	tournamentID, _ := uuid.Parse(str)
+
+	return tournamentID
+}
+
+func toCoreAddresses(strs []string) []common.Address {
+	if len(strs) == 0 {
+		return nil
+	}
+
+	addresses := make([]common.Address, len(strs))
+	for i, str := range strs {
+		addresses[i] = common.HexToAddress(str)
+	}
+
+	return addresses
+}
+
+// =============================================================================
+
+type dbStatsGames struct {
+	Games int `db:"games"`
+	Wins  int `db:"wins"`
+}
+
+type dbStatsCalls struct {
+	Calls      int `db:"calls"`
+	CallsRight int `db:"calls_right"`
+}
+
+// dbRound identifies a round of a game.
+type dbRound struct {
+	ID    uuid.UUID
+	Round int
+}
+
+func toCoreStats(player common.Address, dbGames dbStatsGames, dbCalls dbStatsCalls, dbBets []dbBet, dbCups []dbCup, evts []game.Event) game.Stats {
+	rules := make(map[uuid.UUID]game.Rules)
+	palifico := make(map[dbRound]bool)
+	for _, e := range evts {
+		switch e.Type {
+		case game.EventNew:
+			rules[e.GameID] = e.Rules
+		case game.EventNextRound:
+			palifico[dbRound{ID: e.GameID, Round: e.Round}] = e.Palifico
+		}
+	}
+
+	cups := make(map[dbRound][]game.Cup)
+	for _, dbCup := range dbCups {
+		dice := make([]int, len(dbCup.Dice))
+		for i, d := range dbCup.Dice {
+			dice[i] = int(d)
+		}
+
+		round := dbRound{ID: dbCup.ID, Round: dbCup.Round}
+		cups[round] = append(cups[round], game.Cup{
+			Player: common.HexToAddress(dbCup.Player),
+			Dice:   dice,
+		})
+	}
+
+	stats := game.Stats{
+		Player:     player,
+		Games:      dbGames.Games,
+		Wins:       dbGames.Wins,
+		Calls:      dbCalls.Calls,
+		CallsRight: dbCalls.CallsRight,
+		Bets:       len(dbBets),
+	}
+
+	for _, dbBet := range dbBets {
+		round := dbRound{ID: dbBet.ID, Round: dbBet.Round}
+
+		bet := game.Bet{
+			Player: player,
+			Number: dbBet.Number,
+			Suit:   dbBet.Suit,
+		}
+
+		if rules[dbBet.ID].Bluff(bet, cups[round], palifico[round]) {
+			stats.Bluffs++
+		}
+	}
+
+	return stats
+}
+
+// =============================================================================
+
+type dbSummary struct {
+	ID          uuid.UUID      `db:"game_id"`
+	DateCreated time.Time      `db:"date_created"`
+	AnteUSD     float64        `db:"ante_usd"`
+	Ruleset     string         `db:"ruleset"`
+	Private     bool           `db:"private"`
+	Status      string         `db:"status"`
+	Round       int            `db:"round"`
+	Players     dbarray.String `db:"players"`
+	Winner      string         `db:"winner"`
+}
+
+func toCoreSummaries(dbSums []dbSummary) []game.Summary {
+	sums := make([]game.Summary, len(dbSums))
+	for i, dbSum := range dbSums {
+		var winner common.Address
+		if common.IsHexAddress(dbSum.Winner) {
+			winner = common.HexToAddress(dbSum.Winner)
+		}
+
+		sums[i] = game.Summary{
+			GameID:      dbSum.ID,
+			DateCreated: dbSum.DateCreated,
+			AnteUSD:     dbSum.AnteUSD,
+			Ruleset:     dbSum.Ruleset,
+			Private:     dbSum.Private,
+			Status:      dbSum.Status,
+			Round:       dbSum.Round,
+			Players:     toCoreAddresses(dbSum.Players),
+			Winner:      winner,
+		}
+	}
+
+	return sums
+}
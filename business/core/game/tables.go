@@ -1,10 +1,12 @@
 package game
 
 import (
+	"context"
 	"fmt"
 	"sync"
-	"time"
 
+	"github.com/ardanlabs/ethereum/currency"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
 	"github.com/google/uuid"
 )
 
@@ -12,10 +14,12 @@ import (
 var Tables = newTables()
 
 // tables represent the current set of tables that actively exist. The state
-// of these tables can be of any state. The Add API will remove tables that are
-// older than an hour.
+// of these tables can be of any state. The Reaper removes the tables that are
+// no longer being played.
 type tables struct {
 	games map[uuid.UUID]*Game
+	load  func(ctx context.Context, gameID uuid.UUID) (*Game, error)
+	rater Rater
 	mu    sync.RWMutex
 }
 
@@ -31,41 +35,135 @@ func (t *tables) add(game *Game) {
 	defer t.mu.Unlock()
 
 	t.games[game.id] = game
+}
 
-	// Let's find games that are older than an hour and
-	// remove them from the cache.
-	hour := time.Now().Add(time.Hour)
-	for k, v := range t.games {
-		if v.DateCreated().After(hour) {
-			delete(t.games, k)
-		}
-	}
+// evict removes the specified game from the table management system.
+func (t *tables) evict(key uuid.UUID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.games, key)
 }
 
-// Retrieve returns the specified game from the table management system.
-func (t *tables) Retrieve(key uuid.UUID) (*Game, error) {
+// Retrieve returns the specified game from the table management system. If
+// the game is not in memory, the game is loaded from the store.
+func (t *tables) Retrieve(ctx context.Context, key uuid.UUID) (*Game, error) {
 	t.mu.RLock()
-	defer t.mu.RUnlock()
-
 	game, ok := t.games[key]
-	if !ok {
+	load := t.load
+	t.mu.RUnlock()
+
+	if ok {
+		return game, nil
+	}
+
+	if load == nil {
 		return nil, fmt.Errorf("key %q not found", key)
 	}
 
+	game, err := load(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("key %q not found: %w", key, err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Another request could have loaded the game in the meantime.
+	if existing, ok := t.games[key]; ok {
+		return existing, nil
+	}
+
+	t.games[key] = game
+
 	return game, nil
 }
 
-// Active returns the IDs for all the active games in the system.
-func (t *tables) Active() []uuid.UUID {
+// Rehydrate loads the games that have not been reconciled from the store
+// into the table management system so players can resume them after the
+// engine restarts. Games not found in memory later on are loaded from the
+// store as well. The function returns the number of games that were loaded.
+func (t *tables) Rehydrate(ctx context.Context, log *logger.Logger, converter *currency.Converter, storer Storer, banker Banker, dicer Dicer) (int, error) {
+	load := func(ctx context.Context, gameID uuid.UUID) (*Game, error) {
+		return Load(ctx, log, converter, storer, banker, dicer, gameID)
+	}
+
+	t.mu.Lock()
+	t.load = load
+	t.mu.Unlock()
+
+	gameIDs, err := storer.QueryUnreconciled(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("query unreconciled: %w", err)
+	}
+
+	var loaded int
+	for _, gameID := range gameIDs {
+		g, err := load(ctx, gameID)
+		if err != nil {
+			log.Error(ctx, "tables.rehydrate", "id", gameID, "ERROR", err)
+			continue
+		}
+
+		t.add(g)
+		loaded++
+	}
+
+	return loaded, nil
+}
+
+// SetRater sets the rater used to rate the players of the games that are
+// reconciled.
+func (t *tables) SetRater(rater Rater) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rater = rater
+}
+
+// rate rates the players of a reconciled game. A game is never failed
+// because it couldn't be rated.
+func (t *tables) rate(ctx context.Context, log *logger.Logger, state State) {
+	t.mu.RLock()
+	rater := t.rater
+	t.mu.RUnlock()
+
+	if rater == nil {
+		return
+	}
+
+	if err := rater.Rate(ctx, state); err != nil {
+		log.Error(ctx, "tables.rate", "id", state.GameID, "ERROR", err)
+	}
+}
+
+// all returns all the games in the table management system.
+func (t *tables) all() []*Game {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
+	games := make([]*Game, 0, len(t.games))
+	for _, g := range t.games {
+		games = append(games, g)
+	}
+
+	return games
+}
+
+// Active returns the IDs for all the active games in the system. Private
+// games are not listed. The games are checked without holding the lock, so
+// a game holding its own lock can't block the tables.
+func (t *tables) Active() []uuid.UUID {
 	var ids []uuid.UUID
 
-	for k, v := range t.games {
-		switch v.Status() {
+	for _, g := range t.all() {
+		if g.Private() {
+			continue
+		}
+
+		switch g.Status() {
 		case StatusPlaying, StatusNewGame, StatusRoundOver:
-			ids = append(ids, k)
+			ids = append(ids, g.ID())
 		}
 	}
 
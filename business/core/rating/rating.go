@@ -0,0 +1,208 @@
+// Package rating provides support for rating players with the Elo system
+// based on the games they have won and lost.
+package rating
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for rating players.
+var (
+	ErrNotFound = errors.New("rating not found")
+	ErrRated    = errors.New("game already rated")
+)
+
+// Represents the values used to calculate the ratings.
+const (
+	Initial = 1500 // The rating of a player who hasn't played a game.
+	kFactor = 32   // The most a rating can change against a single opponent.
+)
+
+// Storer interface declares the behaviour this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Rate(ctx context.Context, ratings []Rating, changes []Change) error
+	QueryRated(ctx context.Context, gameID uuid.UUID) (bool, error)
+	QueryByPlayer(ctx context.Context, player common.Address) (Rating, error)
+	Query(ctx context.Context, pageNumber int, rowsPerPage int) ([]Rating, error)
+	Count(ctx context.Context) (int, error)
+}
+
+// Rating represents the rating of a player.
+type Rating struct {
+	Player      common.Address
+	Rating      float64
+	Games       int // The number of rated games played.
+	Wins        int // The number of rated games won.
+	DateUpdated time.Time
+}
+
+// Change represents the change to a player's rating caused by a game.
+type Change struct {
+	GameID      uuid.UUID
+	Player      common.Address
+	Before      float64
+	After       float64
+	DateCreated time.Time
+}
+
+// Core manages the set of APIs for rating access.
+type Core struct {
+	log    *logger.Logger
+	storer Storer
+	mu     sync.Mutex
+}
+
+// NewCore constructs a core for rating api access.
+func NewCore(log *logger.Logger, storer Storer) *Core {
+	return &Core{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Rate updates the ratings of the players of a reconciled game. The winner
+// is rated as beating every other player at the table. A game is only rated
+// once and the ratings of its players are stored together.
+func (c *Core) Rate(ctx context.Context, state game.State) error {
+	if state.Status != game.StatusReconciled {
+		return fmt.Errorf("game status is required to be reconciled: status[%s]", state.Status)
+	}
+
+	// Ratings are read and written in one step so games reconciled at the
+	// same time don't overwrite each other's changes.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rated, err := c.storer.QueryRated(ctx, state.GameID)
+	if err != nil {
+		return fmt.Errorf("query rated: %w", err)
+	}
+
+	if rated {
+		return nil
+	}
+
+	winner, err := c.QueryByPlayer(ctx, state.PlayerLastWin)
+	if err != nil {
+		return fmt.Errorf("query winner: %w", err)
+	}
+
+	var losers []Rating
+	for _, player := range state.ExistingPlayers {
+		if player == state.PlayerLastWin {
+			continue
+		}
+
+		loser, err := c.QueryByPlayer(ctx, player)
+		if err != nil {
+			return fmt.Errorf("query loser: %w", err)
+		}
+		losers = append(losers, loser)
+	}
+
+	if len(losers) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+
+	newWinner, newLosers := Elo(winner, losers)
+
+	before := append([]Rating{winner}, losers...)
+	ratings := append([]Rating{newWinner}, newLosers...)
+	changes := make([]Change, len(ratings))
+
+	for i := range ratings {
+		ratings[i].DateUpdated = now
+
+		changes[i] = Change{
+			GameID:      state.GameID,
+			Player:      ratings[i].Player,
+			Before:      before[i].Rating,
+			After:       ratings[i].Rating,
+			DateCreated: now,
+		}
+	}
+
+	if err := c.storer.Rate(ctx, ratings, changes); err != nil {
+		if errors.Is(err, ErrRated) {
+			return nil
+		}
+		return fmt.Errorf("rate: %w", err)
+	}
+
+	c.log.Info(ctx, "rating.rate", "id", state.GameID, "winner", newWinner.Player, "rating", math.Round(newWinner.Rating), "players", len(losers)+1)
+
+	return nil
+}
+
+// QueryByPlayer returns the rating of the specified player. A player who
+// hasn't played a rated game has the initial rating.
+func (c *Core) QueryByPlayer(ctx context.Context, player common.Address) (Rating, error) {
+	r, err := c.storer.QueryByPlayer(ctx, player)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Rating{Player: player, Rating: Initial}, nil
+		}
+		return Rating{}, fmt.Errorf("query: player[%s]: %w", player, err)
+	}
+
+	return r, nil
+}
+
+// Query returns a page of the ratings from highest to lowest.
+func (c *Core) Query(ctx context.Context, pageNumber int, rowsPerPage int) ([]Rating, error) {
+	ratings, err := c.storer.Query(ctx, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return ratings, nil
+}
+
+// Count returns the number of rated players.
+func (c *Core) Count(ctx context.Context) (int, error) {
+	return c.storer.Count(ctx)
+}
+
+// =============================================================================
+
+// Elo returns the new ratings of the players of a game. The winner is rated
+// as beating each loser, so the points the winner gains are the points the
+// losers give up.
+func Elo(winner Rating, losers []Rating) (Rating, []Rating) {
+	newLosers := make([]Rating, len(losers))
+
+	newWinner := winner
+	newWinner.Games++
+	newWinner.Wins++
+
+	for i, loser := range losers {
+		delta := kFactor * (1 - expected(winner.Rating, loser.Rating))
+
+		newWinner.Rating += delta
+
+		newLosers[i] = loser
+		newLosers[i].Rating -= delta
+		newLosers[i].Games++
+	}
+
+	return newWinner, newLosers
+}
+
+// expected returns the chance the player with rating a beats the player with
+// rating b.
+func expected(a float64, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
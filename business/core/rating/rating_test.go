@@ -0,0 +1,161 @@
+package rating_test
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ardanlabs/liarsdice/business/core/rating"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+func Test_Elo(t *testing.T) {
+	player1 := common.HexToAddress("0x8e113078adf6888b7ba84967f299f29aece24c55")
+	player2 := common.HexToAddress("0x0070742ff6003c3e809e78d524f0fe5dcc5ba7f7")
+	player3 := common.HexToAddress("0x7fdfc99999f1760e8dbd75a480b93c7b8386b79a")
+
+	// Players with the same rating trade half of the k-factor.
+	winner, losers := rating.Elo(
+		rating.Rating{Player: player1, Rating: rating.Initial},
+		[]rating.Rating{{Player: player2, Rating: rating.Initial}},
+	)
+
+	if winner.Rating != rating.Initial+16 {
+		t.Fatalf("expecting the winner to be rated %d; got %v", rating.Initial+16, winner.Rating)
+	}
+
+	if losers[0].Rating != rating.Initial-16 {
+		t.Fatalf("expecting the loser to be rated %d; got %v", rating.Initial-16, losers[0].Rating)
+	}
+
+	if winner.Games != 1 || winner.Wins != 1 || losers[0].Games != 1 || losers[0].Wins != 0 {
+		t.Fatalf("expecting the games and wins to be counted; got winner %+v loser %+v", winner, losers[0])
+	}
+
+	// Beating a stronger player is worth more than beating a weaker one and
+	// the winner gains what the losers give up.
+	winner, losers = rating.Elo(
+		rating.Rating{Player: player1, Rating: 1500},
+		[]rating.Rating{{Player: player2, Rating: 1700}, {Player: player3, Rating: 1300}},
+	)
+
+	stronger := 1700 - losers[0].Rating
+	weaker := 1300 - losers[1].Rating
+	if stronger <= weaker {
+		t.Fatalf("expecting the stronger player to lose more; got stronger %v weaker %v", stronger, weaker)
+	}
+
+	if gained := winner.Rating - 1500; math.Abs(gained-(stronger+weaker)) > 1e-9 {
+		t.Fatalf("expecting the winner to gain %v; got %v", stronger+weaker, gained)
+	}
+}
+
+func Test_RateOnce(t *testing.T) {
+	player1 := common.HexToAddress("0x8e113078adf6888b7ba84967f299f29aece24c55")
+	player2 := common.HexToAddress("0x0070742ff6003c3e809e78d524f0fe5dcc5ba7f7")
+
+	var buf bytes.Buffer
+	log := logger.New(&buf, logger.LevelInfo, "TEST", func(context.Context) string { return "00000000-0000-0000-0000-000000000000" })
+
+	storer := newMemStore()
+	core := rating.NewCore(log, storer)
+
+	state := game.State{
+		GameID:          uuid.New(),
+		Status:          game.StatusReconciled,
+		PlayerLastWin:   player1,
+		ExistingPlayers: []common.Address{player1, player2},
+	}
+
+	if err := core.Rate(context.Background(), state); err != nil {
+		t.Fatalf("unexpected error rating the game: %s", err)
+	}
+
+	if storer.calls != 1 || len(storer.changes[state.GameID]) != 2 {
+		t.Fatalf("expecting the ratings stored in 1 call with 2 changes; got %d calls with %d changes", storer.calls, len(storer.changes[state.GameID]))
+	}
+
+	// A game that was rated by an earlier call doesn't change the ratings.
+	storer.rated = false
+
+	if err := core.Rate(context.Background(), state); err != nil {
+		t.Fatalf("unexpected error rating the game again: %s", err)
+	}
+
+	winner, err := core.QueryByPlayer(context.Background(), player1)
+	if err != nil {
+		t.Fatalf("unexpected error querying the winner: %s", err)
+	}
+
+	if winner.Rating != rating.Initial+16 || winner.Games != 1 {
+		t.Fatalf("expecting the winner to be rated once; got %+v", winner)
+	}
+}
+
+// =============================================================================
+
+// memStore stores the ratings in memory. Like the database, it rejects every
+// write for a game that was already rated.
+type memStore struct {
+	ratings map[common.Address]rating.Rating
+	changes map[uuid.UUID]map[common.Address]rating.Change
+	rated   bool // Reported by QueryRated once a game is rated.
+	calls   int
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		ratings: make(map[common.Address]rating.Rating),
+		changes: make(map[uuid.UUID]map[common.Address]rating.Change),
+	}
+}
+
+func (s *memStore) Rate(ctx context.Context, ratings []rating.Rating, changes []rating.Change) error {
+	s.calls++
+
+	for _, c := range changes {
+		if _, exists := s.changes[c.GameID][c.Player]; exists {
+			return rating.ErrRated
+		}
+	}
+
+	for _, r := range ratings {
+		s.ratings[r.Player] = r
+	}
+
+	for _, c := range changes {
+		if s.changes[c.GameID] == nil {
+			s.changes[c.GameID] = make(map[common.Address]rating.Change)
+		}
+		s.changes[c.GameID][c.Player] = c
+	}
+
+	s.rated = true
+
+	return nil
+}
+
+func (s *memStore) QueryRated(ctx context.Context, gameID uuid.UUID) (bool, error) {
+	return s.rated, nil
+}
+
+func (s *memStore) QueryByPlayer(ctx context.Context, player common.Address) (rating.Rating, error) {
+	r, exists := s.ratings[player]
+	if !exists {
+		return rating.Rating{}, rating.ErrNotFound
+	}
+
+	return r, nil
+}
+
+func (s *memStore) Query(ctx context.Context, pageNumber int, rowsPerPage int) ([]rating.Rating, error) {
+	return nil, nil
+}
+
+func (s *memStore) Count(ctx context.Context) (int, error) {
+	return len(s.ratings), nil
+}
@@ -0,0 +1,64 @@
+package ratingdb
+
+import (
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/core/rating"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+type dbRating struct {
+	Player      string    `db:"player"`
+	Rating      float64   `db:"rating"`
+	Games       int       `db:"games"`
+	Wins        int       `db:"wins"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+type dbChange struct {
+	GameID      uuid.UUID `db:"game_id"`
+	Player      string    `db:"player"`
+	Before      float64   `db:"rating_before"`
+	After       float64   `db:"rating_after"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBRating(r rating.Rating) dbRating {
+	return dbRating{
+		Player:      r.Player.String(),
+		Rating:      r.Rating,
+		Games:       r.Games,
+		Wins:        r.Wins,
+		DateUpdated: r.DateUpdated,
+	}
+}
+
+func toDBChange(c rating.Change) dbChange {
+	return dbChange{
+		GameID:      c.GameID,
+		Player:      c.Player.String(),
+		Before:      c.Before,
+		After:       c.After,
+		DateCreated: c.DateCreated,
+	}
+}
+
+func toCoreRating(dbRtg dbRating) rating.Rating {
+	return rating.Rating{
+		Player:      common.HexToAddress(dbRtg.Player),
+		Rating:      dbRtg.Rating,
+		Games:       dbRtg.Games,
+		Wins:        dbRtg.Wins,
+		DateUpdated: dbRtg.DateUpdated,
+	}
+}
+
+func toCoreRatingSlice(dbRtgs []dbRating) []rating.Rating {
+	ratings := make([]rating.Rating, len(dbRtgs))
+	for i, dbRtg := range dbRtgs {
+		ratings[i] = toCoreRating(dbRtg)
+	}
+
+	return ratings
+}
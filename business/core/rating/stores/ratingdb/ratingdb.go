@@ -0,0 +1,202 @@
+// Package ratingdb contains rating related CRUD functionality.
+package ratingdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardanlabs/liarsdice/business/core/rating"
+	"github.com/ardanlabs/liarsdice/business/data/sqldb"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for rating database access.
+type Store struct {
+	log *logger.Logger
+	db  *sqlx.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Rate adds or replaces the ratings of the players of a game and adds the
+// changes to them in one transaction. A player's rating only changes once for
+// a game, so the transaction fails if the game was already rated.
+func (s *Store) Rate(ctx context.Context, ratings []rating.Rating, changes []rating.Change) error {
+	f := func(tx sqlx.ExtContext) error {
+		for _, r := range ratings {
+			if err := s.upsert(ctx, tx, r); err != nil {
+				return err
+			}
+		}
+
+		for _, c := range changes {
+			if err := s.insertChange(ctx, tx, c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := sqldb.WithinTran(ctx, s.log, s.db, f); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("withintran: %w", rating.ErrRated)
+		}
+		return fmt.Errorf("withintran: %w", err)
+	}
+
+	return nil
+}
+
+// upsert adds or replaces the rating of a player in the db.
+func (s *Store) upsert(ctx context.Context, db sqlx.ExtContext, r rating.Rating) error {
+	q := `
+    INSERT INTO ratings
+        (player, rating, games, wins, date_updated)
+    VALUES
+        (:player, :rating, :games, :wins, :date_updated)
+    ON CONFLICT (player) DO UPDATE SET
+        rating       = EXCLUDED.rating,
+        games        = EXCLUDED.games,
+        wins         = EXCLUDED.wins,
+        date_updated = EXCLUDED.date_updated`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, db, q, toDBRating(r)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// insertChange adds the change to a player's rating caused by a game to the
+// db.
+func (s *Store) insertChange(ctx context.Context, db sqlx.ExtContext, c rating.Change) error {
+	q := `
+    INSERT INTO rating_changes
+        (game_id, player, rating_before, rating_after, date_created)
+    VALUES
+        (:game_id, :player, :rating_before, :rating_after, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, db, q, toDBChange(c)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryRated reports if the players of the specified game have been rated.
+func (s *Store) QueryRated(ctx context.Context, gameID uuid.UUID) (bool, error) {
+	data := struct {
+		ID string `db:"game_id"`
+	}{
+		ID: gameID.String(),
+	}
+
+	q := `
+	SELECT
+		count(1)
+	FROM
+		rating_changes
+	WHERE
+		game_id = :game_id`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return false, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count > 0, nil
+}
+
+// QueryByPlayer gets the rating of the specified player from the db.
+func (s *Store) QueryByPlayer(ctx context.Context, player common.Address) (rating.Rating, error) {
+	data := struct {
+		Player string `db:"player"`
+	}{
+		Player: player.String(),
+	}
+
+	q := `
+	SELECT
+		player,
+		rating,
+		games,
+		wins,
+		date_updated
+	FROM
+		ratings
+	WHERE
+		player = :player`
+
+	var dbRtg dbRating
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRtg); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return rating.Rating{}, fmt.Errorf("namedquerystruct: %w", rating.ErrNotFound)
+		}
+		return rating.Rating{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreRating(dbRtg), nil
+}
+
+// Query gets a page of the ratings from the db from highest to lowest.
+func (s *Store) Query(ctx context.Context, pageNumber int, rowsPerPage int) ([]rating.Rating, error) {
+	data := struct {
+		Offset      int `db:"offset"`
+		RowsPerPage int `db:"rows_per_page"`
+	}{
+		Offset:      (pageNumber - 1) * rowsPerPage,
+		RowsPerPage: rowsPerPage,
+	}
+
+	q := `
+	SELECT
+		player,
+		rating,
+		games,
+		wins,
+		date_updated
+	FROM
+		ratings
+	ORDER BY
+		rating DESC,
+		player
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var dbRtgs []dbRating
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbRtgs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreRatingSlice(dbRtgs), nil
+}
+
+// Count returns the number of rated players in the db.
+func (s *Store) Count(ctx context.Context) (int, error) {
+	q := `
+	SELECT
+		count(1)
+	FROM
+		ratings`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, struct{}{}, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
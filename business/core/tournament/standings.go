@@ -0,0 +1,103 @@
+package tournament
+
+import (
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Seat splits the players between the fewest tables that can seat them.
+// The players are dealt to the tables in order, so the number of players at
+// the tables differs by one at most. A table can end up with a single
+// player, who is given a bye.
+func Seat(players []common.Address, tableSize int) [][]common.Address {
+	if len(players) == 0 || tableSize < 1 {
+		return nil
+	}
+
+	n := (len(players) + tableSize - 1) / tableSize
+
+	tables := make([][]common.Address, n)
+	for i, player := range players {
+		tables[i%n] = append(tables[i%n], player)
+	}
+
+	return tables
+}
+
+// Standings returns the players of a game that is over from first to last
+// place. The winner is first and the other players are placed in the reverse
+// order they were knocked out of the game.
+func Standings(events []game.Event, rules game.Rules) []common.Address {
+	var players []common.Address
+	var out []common.Address
+	var winner common.Address
+
+	outs := make(map[common.Address]int)
+	knockedOut := make(map[common.Address]bool)
+
+	knockOut := func(player common.Address) {
+		if !knockedOut[player] {
+			knockedOut[player] = true
+			out = append(out, player)
+		}
+	}
+
+	for _, e := range events {
+		switch e.Type {
+		case game.EventJoin:
+			players = append(players, e.Player)
+
+		case game.EventLeave:
+
+			// A player who leaves before the game starts gives up their
+			// seat and isn't placed.
+			if e.Status == "" {
+				var seated []common.Address
+				for _, player := range players {
+					if player != e.Player {
+						seated = append(seated, player)
+					}
+				}
+				players = seated
+				break
+			}
+			knockOut(e.Player)
+
+		case game.EventOut:
+			outs[e.Player] = e.Outs
+			if outs[e.Player] >= rules.MaxOuts() {
+				knockOut(e.Player)
+			}
+
+		case game.EventLiar, game.EventExact, game.EventForfeit:
+			outs[e.Loser]++
+			if outs[e.Loser] >= rules.MaxOuts() {
+				knockOut(e.Loser)
+			}
+
+		case game.EventReconcile:
+			winner = e.Player
+		}
+	}
+
+	var empty common.Address
+	var standings []common.Address
+
+	if winner != empty {
+		standings = append(standings, winner)
+	}
+
+	for _, player := range players {
+		if player != winner && !knockedOut[player] {
+			standings = append(standings, player)
+		}
+	}
+
+	for i := len(out) - 1; i >= 0; i-- {
+		if out[i] != winner {
+			standings = append(standings, out[i])
+		}
+	}
+
+	return standings
+}
@@ -0,0 +1,132 @@
+package tournamentdb
+
+import (
+	"time"
+
+	"github.com/ardanlabs/liarsdice/business/core/tournament"
+	"github.com/ardanlabs/liarsdice/business/data/sqldb/dbarray"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+type dbTournament struct {
+	ID          uuid.UUID      `db:"tournament_id"`
+	Creator     string         `db:"creator"`
+	BuyInUSD    float64        `db:"buy_in_usd"`
+	TableSize   int            `db:"table_size"`
+	PrizeSplit  dbarray.Int64  `db:"prize_split"`
+	Status      string         `db:"status"`
+	Stage       int            `db:"stage"`
+	Paid        int            `db:"paid"`
+	Places      dbarray.String `db:"places"`
+	DateCreated time.Time      `db:"date_created"`
+}
+
+type dbPlayer struct {
+	ID     uuid.UUID `db:"tournament_id"`
+	Player string    `db:"player"`
+	Seat   int       `db:"seat"`
+}
+
+type dbTable struct {
+	ID        uuid.UUID      `db:"tournament_id"`
+	Stage     int            `db:"stage"`
+	Number    int            `db:"number"`
+	GameID    uuid.UUID      `db:"game_id"`
+	Players   dbarray.String `db:"players"`
+	Standings dbarray.String `db:"standings"`
+	Finished  bool           `db:"finished"`
+}
+
+func toDBTournament(t tournament.Tournament) dbTournament {
+	split := make([]int64, len(t.PrizeSplit))
+	for i, pct := range t.PrizeSplit {
+		split[i] = int64(pct)
+	}
+
+	return dbTournament{
+		ID:          t.ID,
+		Creator:     t.Creator.String(),
+		BuyInUSD:    t.BuyInUSD,
+		TableSize:   t.TableSize,
+		PrizeSplit:  split,
+		Status:      t.Status,
+		Stage:       t.Stage,
+		Paid:        t.Paid,
+		Places:      toDBAddresses(t.Places),
+		DateCreated: t.DateCreated,
+	}
+}
+
+func toDBTable(tournamentID uuid.UUID, tb tournament.Table) dbTable {
+	return dbTable{
+		ID:        tournamentID,
+		Stage:     tb.Stage,
+		Number:    tb.Number,
+		GameID:    tb.GameID,
+		Players:   toDBAddresses(tb.Players),
+		Standings: toDBAddresses(tb.Standings),
+		Finished:  tb.Finished,
+	}
+}
+
+func toCoreTournament(dbTour dbTournament, dbPlayers []dbPlayer, dbTables []dbTable) tournament.Tournament {
+	split := make([]int, len(dbTour.PrizeSplit))
+	for i, pct := range dbTour.PrizeSplit {
+		split[i] = int(pct)
+	}
+
+	players := make([]common.Address, len(dbPlayers))
+	for i, dbPlayer := range dbPlayers {
+		players[i] = common.HexToAddress(dbPlayer.Player)
+	}
+
+	tables := make([]tournament.Table, len(dbTables))
+	for i, dbTable := range dbTables {
+		tables[i] = tournament.Table{
+			Stage:     dbTable.Stage,
+			Number:    dbTable.Number,
+			GameID:    dbTable.GameID,
+			Players:   toCoreAddresses(dbTable.Players),
+			Standings: toCoreAddresses(dbTable.Standings),
+			Finished:  dbTable.Finished,
+		}
+	}
+
+	return tournament.Tournament{
+		ID:          dbTour.ID,
+		Creator:     common.HexToAddress(dbTour.Creator),
+		BuyInUSD:    dbTour.BuyInUSD,
+		TableSize:   dbTour.TableSize,
+		PrizeSplit:  split,
+		Status:      dbTour.Status,
+		Stage:       dbTour.Stage,
+		Paid:        dbTour.Paid,
+		Players:     players,
+		Tables:      tables,
+		Places:      toCoreAddresses(dbTour.Places),
+		DateCreated: dbTour.DateCreated,
+	}
+}
+
+func toDBAddresses(addresses []common.Address) []string {
+	strs := make([]string, len(addresses))
+	for i, address := range addresses {
+		strs[i] = address.String()
+	}
+
+	return strs
+}
+
+func toCoreAddresses(strs []string) []common.Address {
+	if len(strs) == 0 {
+		return nil
+	}
+
+	addresses := make([]common.Address, len(strs))
+	for i, str := range strs {
+		addresses[i] = common.HexToAddress(str)
+	}
+
+	return addresses
+}
@@ -0,0 +1,229 @@
+// Package tournamentdb contains tournament related CRUD functionality.
+package tournamentdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardanlabs/liarsdice/business/core/tournament"
+	"github.com/ardanlabs/liarsdice/business/data/sqldb"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for tournament database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create adds a tournament to the db.
+func (s *Store) Create(ctx context.Context, t tournament.Tournament) error {
+	q := `
+    INSERT INTO tournaments
+        (tournament_id, creator, buy_in_usd, table_size, prize_split, status, stage, paid, places, date_created)
+    VALUES
+        (:tournament_id, :creator, :buy_in_usd, :table_size, :prize_split, :status, :stage, :paid, :places, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBTournament(t)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces the progress of a tournament in the db.
+func (s *Store) Update(ctx context.Context, t tournament.Tournament) error {
+	q := `
+    UPDATE
+        tournaments
+    SET
+        status = :status,
+        stage  = :stage,
+        paid   = :paid,
+        places = :places
+    WHERE
+        tournament_id = :tournament_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBTournament(t)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// InsertPlayer adds a registered player to the tournament in the db.
+func (s *Store) InsertPlayer(ctx context.Context, tournamentID uuid.UUID, player common.Address, seat int) error {
+	d := dbPlayer{
+		ID:     tournamentID,
+		Player: player.String(),
+		Seat:   seat,
+	}
+
+	q := `
+    INSERT INTO tournament_players
+        (tournament_id, player, seat)
+    VALUES
+        (:tournament_id, :player, :seat)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, d); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// InsertTable adds a table of the tournament to the db.
+func (s *Store) InsertTable(ctx context.Context, tournamentID uuid.UUID, tb tournament.Table) error {
+	q := `
+    INSERT INTO tournament_tables
+        (tournament_id, stage, number, game_id, players, standings, finished)
+    VALUES
+        (:tournament_id, :stage, :number, :game_id, :players, :standings, :finished)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBTable(tournamentID, tb)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateTable replaces the standings of a table in the db.
+func (s *Store) UpdateTable(ctx context.Context, tournamentID uuid.UUID, tb tournament.Table) error {
+	q := `
+    UPDATE
+        tournament_tables
+    SET
+        standings = :standings,
+        finished  = :finished
+    WHERE
+        tournament_id = :tournament_id AND
+        stage = :stage AND
+        number = :number`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBTable(tournamentID, tb)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified tournament with its players and tables from
+// the db.
+func (s *Store) QueryByID(ctx context.Context, tournamentID uuid.UUID) (tournament.Tournament, error) {
+	data := struct {
+		ID string `db:"tournament_id"`
+	}{
+		ID: tournamentID.String(),
+	}
+
+	q := `
+	SELECT
+		tournament_id,
+		creator,
+		buy_in_usd,
+		table_size,
+		prize_split,
+		status,
+		stage,
+		paid,
+		places,
+		date_created
+	FROM
+		tournaments
+	WHERE
+		tournament_id = :tournament_id`
+
+	var dbTour dbTournament
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbTour); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return tournament.Tournament{}, fmt.Errorf("namedquerystruct: %w", tournament.ErrNotFound)
+		}
+		return tournament.Tournament{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	q = `
+	SELECT
+		tournament_id,
+		player,
+		seat
+	FROM
+		tournament_players
+	WHERE
+		tournament_id = :tournament_id
+	ORDER BY
+		seat`
+
+	var dbPlayers []dbPlayer
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPlayers); err != nil {
+		return tournament.Tournament{}, fmt.Errorf("namedqueryslice-players: %w", err)
+	}
+
+	q = `
+	SELECT
+		tournament_id,
+		stage,
+		number,
+		game_id,
+		players,
+		standings,
+		finished
+	FROM
+		tournament_tables
+	WHERE
+		tournament_id = :tournament_id
+	ORDER BY
+		stage,
+		number`
+
+	var dbTables []dbTable
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbTables); err != nil {
+		return tournament.Tournament{}, fmt.Errorf("namedqueryslice-tables: %w", err)
+	}
+
+	return toCoreTournament(dbTour, dbPlayers, dbTables), nil
+}
+
+// QueryActive gets the ids of the tournaments that are open or being played.
+func (s *Store) QueryActive(ctx context.Context) ([]uuid.UUID, error) {
+	data := struct {
+		Open    string `db:"open"`
+		Playing string `db:"playing"`
+	}{
+		Open:    tournament.StatusOpen,
+		Playing: tournament.StatusPlaying,
+	}
+
+	q := `
+	SELECT
+		tournament_id
+	FROM
+		tournaments
+	WHERE
+		status IN (:open, :playing)`
+
+	var dbTours []struct {
+		ID uuid.UUID `db:"tournament_id"`
+	}
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbTours); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	tournamentIDs := make([]uuid.UUID, len(dbTours))
+	for i, dbTour := range dbTours {
+		tournamentIDs[i] = dbTour.ID
+	}
+
+	return tournamentIDs, nil
+}
@@ -0,0 +1,697 @@
+// Package tournament provides support for elimination tournaments played
+// over many tables of liar's dice.
+package tournament
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ardanlabs/ethereum/currency"
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ardanlabs/liarsdice/foundation/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+var ErrNotFound = errors.New("tournament not found")
+
+// Represents the different statuses of a tournament.
+const (
+	StatusOpen      = "open"
+	StatusPlaying   = "playing"
+	StatusSettled   = "settled"
+	StatusCancelled = "cancelled"
+)
+
+// Storer interface declares the behaviour this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, t Tournament) error
+	Update(ctx context.Context, t Tournament) error
+	InsertPlayer(ctx context.Context, tournamentID uuid.UUID, player common.Address, seat int) error
+	InsertTable(ctx context.Context, tournamentID uuid.UUID, tb Table) error
+	UpdateTable(ctx context.Context, tournamentID uuid.UUID, tb Table) error
+	QueryByID(ctx context.Context, tournamentID uuid.UUID) (Tournament, error)
+	QueryActive(ctx context.Context) ([]uuid.UUID, error)
+}
+
+// Tournament represents an elimination tournament. The winners of the tables
+// in a stage advance to the next stage until one table is left. The winner
+// of that table wins the tournament.
+type Tournament struct {
+	ID          uuid.UUID
+	Creator     common.Address
+	BuyInUSD    float64
+	TableSize   int              // The most players seated at a table.
+	PrizeSplit  []int            // The percentage of the pool paid to each place.
+	Status      string           // Current status of the tournament.
+	Stage       int              // The stage being played.
+	Paid        int              // The number of places that have been paid.
+	Players     []common.Address // The players in the order they registered.
+	Tables      []Table          // The tables of every stage.
+	Places      []common.Address // The players from first to last place once settled.
+	DateCreated time.Time
+}
+
+// Table represents a table in a stage of the tournament. A table with a single
+// player is a bye and the player advances without playing.
+type Table struct {
+	Stage     int
+	Number    int
+	GameID    uuid.UUID
+	Players   []common.Address // The players in seating order.
+	Standings []common.Address // The players from first to last place.
+	Finished  bool
+}
+
+// Manager runs the tournaments. It starts the games for every stage, checks
+// the games on an interval and advances the winners. The funds are settled
+// with the bank once a tournament is over.
+type Manager struct {
+	log         *logger.Logger
+	converter   *currency.Converter
+	storer      Storer
+	gameStorer  game.Storer
+	banker      game.Banker
+	dicer       game.Dicer
+	rules       game.Rules
+	interval    time.Duration
+	bankTimeout time.Duration
+	mu          sync.Mutex
+	tournaments map[uuid.UUID]*Tournament
+	shutdown    chan struct{}
+	wg          sync.WaitGroup
+}
+
+// New constructs a manager that plays the tournament games with the
+// specified rules and checks the games on the specified interval.
+func New(log *logger.Logger, converter *currency.Converter, storer Storer, gameStorer game.Storer, banker game.Banker, dicer game.Dicer, rules game.Rules, interval time.Duration, bankTimeout time.Duration) *Manager {
+	return &Manager{
+		log:         log,
+		converter:   converter,
+		storer:      storer,
+		gameStorer:  gameStorer,
+		banker:      banker,
+		dicer:       dicer,
+		rules:       rules,
+		interval:    interval,
+		bankTimeout: bankTimeout,
+		tournaments: make(map[uuid.UUID]*Tournament),
+		shutdown:    make(chan struct{}),
+	}
+}
+
+// Create creates a new tournament and registers the creator. An empty prize
+// split pays the whole pool to the winner.
+func (m *Manager) Create(ctx context.Context, creator common.Address, buyInUSD float64, tableSize int, prizeSplit []int) (Tournament, error) {
+	if buyInUSD <= 0 {
+		return Tournament{}, fmt.Errorf("buy-in must be greater than zero: buyin[%v]", buyInUSD)
+	}
+
+	if tableSize < 2 || tableSize > m.rules.MaxPlayers {
+		return Tournament{}, fmt.Errorf("table size must be between 2 and %d: size[%d]", m.rules.MaxPlayers, tableSize)
+	}
+
+	if len(prizeSplit) == 0 {
+		prizeSplit = []int{100}
+	}
+
+	var total int
+	for _, pct := range prizeSplit {
+		if pct <= 0 {
+			return Tournament{}, fmt.Errorf("prize split must be greater than zero: split[%v]", prizeSplit)
+		}
+		total += pct
+	}
+
+	if total != 100 {
+		return Tournament{}, fmt.Errorf("prize split must add up to 100: split[%v]", prizeSplit)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := Tournament{
+		ID:          uuid.New(),
+		Creator:     creator,
+		BuyInUSD:    buyInUSD,
+		TableSize:   tableSize,
+		PrizeSplit:  prizeSplit,
+		Status:      StatusOpen,
+		DateCreated: time.Now().UTC(),
+	}
+
+	if err := m.storer.Create(ctx, t); err != nil {
+		return Tournament{}, fmt.Errorf("create: %w", err)
+	}
+
+	if err := m.register(ctx, &t, creator); err != nil {
+		t.Status = StatusCancelled
+		if err := m.storer.Update(ctx, t); err != nil {
+			m.log.Error(ctx, "tournament.create.update", "id", t.ID, "ERROR", err)
+		}
+
+		return Tournament{}, err
+	}
+
+	m.tournaments[t.ID] = &t
+
+	m.log.Info(ctx, "tournament.create", "id", t.ID, "creator", creator, "buyInUSD", buyInUSD, "tableSize", tableSize, "prizeSplit", prizeSplit)
+
+	return t.copy(), nil
+}
+
+// Register adds the player to a tournament that hasn't started. The buy-in
+// is reserved from the player's balance until the tournament is settled.
+func (m *Manager) Register(ctx context.Context, tournamentID uuid.UUID, player common.Address) (Tournament, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, exists := m.tournaments[tournamentID]
+	if !exists {
+		return Tournament{}, ErrNotFound
+	}
+
+	if t.Status != StatusOpen {
+		return Tournament{}, fmt.Errorf("tournament status is required to be open: status[%s]", t.Status)
+	}
+
+	if err := m.register(ctx, t, player); err != nil {
+		return Tournament{}, err
+	}
+
+	m.log.Info(ctx, "tournament.register", "id", t.ID, "player", player, "players", len(t.Players))
+
+	return t.copy(), nil
+}
+
+// Begin seats the registered players at the tables of the first stage and
+// starts the games.
+func (m *Manager) Begin(ctx context.Context, tournamentID uuid.UUID) (Tournament, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, exists := m.tournaments[tournamentID]
+	if !exists {
+		return Tournament{}, ErrNotFound
+	}
+
+	if t.Status != StatusOpen {
+		return Tournament{}, fmt.Errorf("tournament status is required to be open: status[%s]", t.Status)
+	}
+
+	if len(t.Players) < 2 {
+		return Tournament{}, fmt.Errorf("not enough players to start the tournament: players[%d]", len(t.Players))
+	}
+
+	if len(t.PrizeSplit) > len(t.Players) {
+		return Tournament{}, fmt.Errorf("prize split pays more places than there are players: places[%d] players[%d]", len(t.PrizeSplit), len(t.Players))
+	}
+
+	t.Status = StatusPlaying
+
+	if err := m.startStage(ctx, t, t.Players); err != nil {
+		m.cancel(ctx, t)
+		delete(m.tournaments, t.ID)
+		return Tournament{}, fmt.Errorf("start stage: %w", err)
+	}
+
+	if err := m.storer.Update(ctx, *t); err != nil {
+		m.log.Error(ctx, "tournament.begin.update", "id", t.ID, "ERROR", err)
+	}
+
+	m.log.Info(ctx, "tournament.begin", "id", t.ID, "players", len(t.Players))
+
+	return t.copy(), nil
+}
+
+// Retrieve returns the specified tournament. Tournaments that are over are
+// loaded from the store.
+func (m *Manager) Retrieve(ctx context.Context, tournamentID uuid.UUID) (Tournament, error) {
+	m.mu.Lock()
+	t, exists := m.tournaments[tournamentID]
+	var c Tournament
+	if exists {
+		c = t.copy()
+	}
+	m.mu.Unlock()
+
+	if exists {
+		return c, nil
+	}
+
+	tour, err := m.storer.QueryByID(ctx, tournamentID)
+	if err != nil {
+		return Tournament{}, fmt.Errorf("query: %w", err)
+	}
+
+	return tour, nil
+}
+
+// Active returns the tournaments that are open or being played, oldest
+// first.
+func (m *Manager) Active() []Tournament {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tournaments := make([]Tournament, 0, len(m.tournaments))
+	for _, t := range m.tournaments {
+		tournaments = append(tournaments, t.copy())
+	}
+
+	sort.Slice(tournaments, func(i, j int) bool {
+		return tournaments[i].DateCreated.Before(tournaments[j].DateCreated)
+	})
+
+	return tournaments
+}
+
+// Rehydrate loads the tournaments that are open or being played from the
+// store so they continue after the engine restarts. The buy-ins are reserved
+// again. The function returns the number of tournaments that were loaded.
+func (m *Manager) Rehydrate(ctx context.Context) (int, error) {
+	tournamentIDs, err := m.storer.QueryActive(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("query active: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var loaded int
+	for _, tournamentID := range tournamentIDs {
+		t, err := m.storer.QueryByID(ctx, tournamentID)
+		if err != nil {
+			m.log.Error(ctx, "tournament.rehydrate", "id", tournamentID, "ERROR", err)
+			continue
+		}
+
+		buyInGWei := m.converter.USD2GWei(big.NewFloat(t.BuyInUSD))
+		for _, player := range t.Players {
+			balance, err := m.banker.AccountBalance(ctx, player)
+			if err == nil {
+				err = game.Escrow.Reserve(t.ID, player, balance, buyInGWei)
+			}
+
+			if err != nil {
+				m.log.Error(ctx, "tournament.rehydrate.reserve", "id", t.ID, "player", player, "ERROR", err)
+			}
+		}
+
+		m.tournaments[t.ID] = &t
+		loaded++
+	}
+
+	return loaded, nil
+}
+
+// Start begins checking the tournament games in a goroutine.
+func (m *Manager) Start() {
+	m.wg.Add(1)
+
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				m.check()
+
+			case <-m.shutdown:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the manager and waits for the goroutine to terminate.
+func (m *Manager) Shutdown() {
+	close(m.shutdown)
+	m.wg.Wait()
+}
+
+// check advances every tournament being played. The tournaments are
+// advanced on copies without holding the lock, so the calls to the bank
+// don't block the other tournaments. Only this goroutine changes a
+// tournament once it's being played.
+func (m *Manager) check() {
+	ctx := context.Background()
+
+	m.mu.Lock()
+	var playing []Tournament
+	for _, t := range m.tournaments {
+		if t.Status == StatusPlaying {
+			playing = append(playing, t.copy())
+		}
+	}
+	m.mu.Unlock()
+
+	for _, t := range playing {
+		m.advance(ctx, &t)
+
+		m.mu.Lock()
+		switch t.Status {
+		case StatusSettled, StatusCancelled:
+			delete(m.tournaments, t.ID)
+		default:
+			m.tournaments[t.ID] = &t
+		}
+		m.mu.Unlock()
+	}
+}
+
+// advance records the standings of the tables in the current stage that
+// are over. Once every table is over, the winners advance to the next stage
+// or the tournament is settled when only one winner is left.
+func (m *Manager) advance(ctx context.Context, t *Tournament) {
+	done := true
+
+	for i := range t.Tables {
+		tb := &t.Tables[i]
+		if tb.Stage != t.Stage || tb.Finished {
+			continue
+		}
+
+		g, err := game.Tables.Retrieve(ctx, tb.GameID)
+		if err != nil {
+			m.log.Error(ctx, "tournament.advance.retrieve", "id", t.ID, "game", tb.GameID, "ERROR", err)
+			done = false
+			continue
+		}
+
+		// No funds are moved when a tournament game is reconciled.
+		switch g.Status() {
+		case game.StatusGameOver:
+			if _, _, err := g.Reconcile(ctx); err != nil {
+				m.log.Error(ctx, "tournament.advance.reconcile", "id", t.ID, "game", tb.GameID, "ERROR", err)
+				done = false
+				continue
+			}
+			fallthrough
+
+		case game.StatusReconciled:
+			tb.Standings = Standings(g.Events(), g.State().Rules)
+
+		case game.StatusAbandoned:
+
+		default:
+			done = false
+			continue
+		}
+
+		tb.Finished = true
+
+		if err := m.storer.UpdateTable(ctx, t.ID, *tb); err != nil {
+			m.log.Error(ctx, "tournament.advance.updatetable", "id", t.ID, "game", tb.GameID, "ERROR", err)
+		}
+
+		m.log.Info(ctx, "tournament.advance.table", "id", t.ID, "stage", tb.Stage, "table", tb.Number, "standings", len(tb.Standings))
+	}
+
+	if !done {
+		return
+	}
+
+	// Nobody advances from a table that was abandoned.
+	var winners []common.Address
+	for _, tb := range t.Tables {
+		if tb.Stage == t.Stage && len(tb.Standings) > 0 {
+			winners = append(winners, tb.Standings[0])
+		}
+	}
+
+	switch len(winners) {
+	case 0:
+		m.cancel(ctx, t)
+
+	case 1:
+		m.settle(ctx, t)
+
+	default:
+		if err := m.startStage(ctx, t, winners); err != nil {
+			m.log.Error(ctx, "tournament.advance.startstage", "id", t.ID, "ERROR", err)
+			m.cancel(ctx, t)
+			return
+		}
+
+		if err := m.storer.Update(ctx, *t); err != nil {
+			m.log.Error(ctx, "tournament.advance.update", "id", t.ID, "ERROR", err)
+		}
+	}
+}
+
+// startStage seats the players at the tables of the next stage and starts
+// the games.
+func (m *Manager) startStage(ctx context.Context, t *Tournament, players []common.Address) error {
+	t.Stage++
+
+	for i, seats := range Seat(players, t.TableSize) {
+		tb := Table{
+			Stage:   t.Stage,
+			Number:  i + 1,
+			Players: seats,
+		}
+
+		switch len(seats) {
+		case 1:
+			tb.Standings = seats
+			tb.Finished = true
+
+		default:
+			g, err := m.newGame(ctx, t, seats)
+			if err != nil {
+				return err
+			}
+			tb.GameID = g.ID()
+		}
+
+		t.Tables = append(t.Tables, tb)
+
+		if err := m.storer.InsertTable(ctx, t.ID, tb); err != nil {
+			return fmt.Errorf("insert table: %w", err)
+		}
+	}
+
+	m.log.Info(ctx, "tournament.stage", "id", t.ID, "stage", t.Stage, "players", len(players))
+
+	return nil
+}
+
+// newGame creates and starts the game for a table. The table is private to
+// the players seated at it.
+func (m *Manager) newGame(ctx context.Context, t *Tournament, seats []common.Address) (*game.Game, error) {
+	rules := m.rules
+	rules.MinPlayers = len(seats)
+	rules.MaxPlayers = len(seats)
+	rules.Private = true
+	rules.Allowlist = seats
+	rules.TournamentID = t.ID
+
+	g, err := game.New(ctx, m.log, m.converter, m.gameStorer, m.banker, m.dicer, seats[0], t.BuyInUSD, rules)
+	if err != nil {
+		return nil, fmt.Errorf("new game: %w", err)
+	}
+
+	seat := func() error {
+		for _, player := range seats[1:] {
+			if err := g.AddAccount(ctx, player); err != nil {
+				return err
+			}
+		}
+
+		return g.StartGame(ctx)
+	}
+
+	if err := seat(); err != nil {
+		if err := g.Abandon(ctx); err != nil {
+			m.log.Error(ctx, "tournament.newgame.abandon", "id", t.ID, "game", g.ID(), "ERROR", err)
+		}
+
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// settle pays the prize split to the places with the bank. The bank pays a
+// single winner per call, so each place is paid with its own call. Every
+// other player pays their share of the place's prize.
+func (m *Manager) settle(ctx context.Context, t *Tournament) {
+	ctx, cancel := context.WithTimeout(ctx, m.bankTimeout)
+	defer cancel()
+
+	places := t.places()
+	buyInGWei := m.converter.USD2GWei(big.NewFloat(t.BuyInUSD))
+
+	for ; t.Paid < len(t.PrizeSplit); t.Paid++ {
+		winner := places[t.Paid]
+
+		var losers []common.Address
+		for _, player := range t.Players {
+			if player != winner {
+				losers = append(losers, player)
+			}
+		}
+
+		// The contract pays the winner the pot less the fee, so using the
+		// share as the fee pays the winner the share from every loser.
+		shareGWei := new(big.Float).Mul(buyInGWei, big.NewFloat(float64(t.PrizeSplit[t.Paid])/100))
+
+		if _, _, err := m.banker.Reconcile(ctx, winner, losers, shareGWei, shareGWei); err != nil {
+			m.log.Error(ctx, "tournament.settle.reconcile", "id", t.ID, "place", t.Paid+1, "player", winner, "ERROR", err)
+
+			// The places already paid are stored so they are not paid
+			// again when the settlement is retried.
+			if err := m.storer.Update(ctx, *t); err != nil {
+				m.log.Error(ctx, "tournament.settle.update", "id", t.ID, "ERROR", err)
+			}
+
+			return
+		}
+
+		m.log.Info(ctx, "tournament.settle.place", "id", t.ID, "place", t.Paid+1, "player", winner, "shareGWei", shareGWei)
+	}
+
+	t.Places = places
+	t.Status = StatusSettled
+
+	game.Escrow.ReleaseAll(t.ID)
+
+	if err := m.storer.Update(ctx, *t); err != nil {
+		m.log.Error(ctx, "tournament.settle.update", "id", t.ID, "ERROR", err)
+	}
+
+	m.log.Info(ctx, "tournament.settle", "id", t.ID, "winner", places[0])
+}
+
+// cancel stops a tournament that can't be finished. The games still being
+// played are abandoned and no funds are moved.
+func (m *Manager) cancel(ctx context.Context, t *Tournament) {
+	for _, tb := range t.Tables {
+		if tb.Finished || tb.GameID == uuid.Nil {
+			continue
+		}
+
+		g, err := game.Tables.Retrieve(ctx, tb.GameID)
+		if err != nil {
+			continue
+		}
+
+		if err := g.Abandon(ctx); err != nil {
+			m.log.Error(ctx, "tournament.cancel.abandon", "id", t.ID, "game", tb.GameID, "ERROR", err)
+		}
+	}
+
+	t.Status = StatusCancelled
+
+	game.Escrow.ReleaseAll(t.ID)
+
+	if err := m.storer.Update(ctx, *t); err != nil {
+		m.log.Error(ctx, "tournament.cancel.update", "id", t.ID, "ERROR", err)
+	}
+
+	m.log.Info(ctx, "tournament.cancel", "id", t.ID, "stage", t.Stage)
+}
+
+// register reserves the buy-in from the player's balance and adds the player
+// to the tournament.
+func (m *Manager) register(ctx context.Context, t *Tournament, player common.Address) error {
+	for _, p := range t.Players {
+		if p == player {
+			return fmt.Errorf("player [%s] is already registered", player)
+		}
+	}
+
+	balance, err := m.banker.AccountBalance(ctx, player)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve account[%s] balance", player)
+	}
+
+	buyInGWei := m.converter.USD2GWei(big.NewFloat(t.BuyInUSD))
+	if err := game.Escrow.Reserve(t.ID, player, balance, buyInGWei); err != nil {
+		return err
+	}
+
+	if err := m.storer.InsertPlayer(ctx, t.ID, player, len(t.Players)); err != nil {
+		game.Escrow.Release(t.ID, player, buyInGWei)
+		return fmt.Errorf("insert player: %w", err)
+	}
+
+	t.Players = append(t.Players, player)
+
+	return nil
+}
+
+// =============================================================================
+
+// places returns the players from first to last place. Players who went
+// further in the tournament place higher. Players knocked out in the same
+// stage are placed by where they finished at their table.
+func (t Tournament) places() []common.Address {
+	var places []common.Address
+	placed := make(map[common.Address]bool)
+
+	add := func(player common.Address) {
+		if !placed[player] {
+			placed[player] = true
+			places = append(places, player)
+		}
+	}
+
+	for stage := t.Stage; stage >= 1; stage-- {
+		var tables []Table
+		for _, tb := range t.Tables {
+			if tb.Stage == stage {
+				tables = append(tables, tb)
+			}
+		}
+
+		for pos := 0; ; pos++ {
+			var more bool
+			for _, tb := range tables {
+				if pos < len(tb.Standings) {
+					more = true
+					add(tb.Standings[pos])
+				}
+			}
+
+			if !more {
+				break
+			}
+		}
+
+		// The players at a table that was abandoned place last in the stage.
+		for _, tb := range tables {
+			for _, player := range tb.Players {
+				add(player)
+			}
+		}
+	}
+
+	return places
+}
+
+// copy returns a copy of the tournament that doesn't share any memory.
+func (t Tournament) copy() Tournament {
+	c := t
+	c.PrizeSplit = append([]int(nil), t.PrizeSplit...)
+	c.Players = append([]common.Address(nil), t.Players...)
+	c.Places = append([]common.Address(nil), t.Places...)
+
+	c.Tables = make([]Table, len(t.Tables))
+	for i, tb := range t.Tables {
+		tb.Players = append([]common.Address(nil), tb.Players...)
+		tb.Standings = append([]common.Address(nil), tb.Standings...)
+		c.Tables[i] = tb
+	}
+
+	return c
+}
@@ -0,0 +1,101 @@
+package tournament_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ardanlabs/liarsdice/business/core/game"
+	"github.com/ardanlabs/liarsdice/business/core/tournament"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_Seat(t *testing.T) {
+	var players []common.Address
+	for i := 1; i <= 7; i++ {
+		players = append(players, common.BigToAddress(big.NewInt(int64(i))))
+	}
+
+	tests := []struct {
+		players   int
+		tableSize int
+		tables    []int
+	}{
+		{players: 2, tableSize: 5, tables: []int{2}},
+		{players: 5, tableSize: 5, tables: []int{5}},
+		{players: 7, tableSize: 5, tables: []int{4, 3}},
+		{players: 7, tableSize: 3, tables: []int{3, 2, 2}},
+		{players: 3, tableSize: 2, tables: []int{2, 1}},
+	}
+
+	for _, tt := range tests {
+		tables := tournament.Seat(players[:tt.players], tt.tableSize)
+
+		if len(tables) != len(tt.tables) {
+			t.Fatalf("players[%d] size[%d]: expecting %d tables; got %d", tt.players, tt.tableSize, len(tt.tables), len(tables))
+		}
+
+		seated := make(map[common.Address]bool)
+		for i, seats := range tables {
+			if len(seats) != tt.tables[i] {
+				t.Fatalf("players[%d] size[%d]: expecting %d players at table %d; got %d", tt.players, tt.tableSize, tt.tables[i], i, len(seats))
+			}
+
+			for _, player := range seats {
+				if seated[player] {
+					t.Fatalf("players[%d] size[%d]: player [%s] is seated twice", tt.players, tt.tableSize, player)
+				}
+				seated[player] = true
+			}
+		}
+	}
+
+	if tables := tournament.Seat(nil, 5); tables != nil {
+		t.Fatalf("expecting no tables without players; got %d", len(tables))
+	}
+}
+
+func Test_Standings(t *testing.T) {
+	player1 := common.HexToAddress("0x8e113078adf6888b7ba84967f299f29aece24c55")
+	player2 := common.HexToAddress("0x0070742ff6003c3e809e78d524f0fe5dcc5ba7f7")
+	player3 := common.HexToAddress("0x7fdfc99999f1760e8dbd75a480b93c7b8386b79a")
+	player4 := common.HexToAddress("0x000cf95cb5eb168f57d0befcdf6a201e3e1acea9")
+
+	rules, err := game.ParseRules(game.RulesetClassic)
+	if err != nil {
+		t.Fatalf("should be able to parse the rules: %s", err)
+	}
+
+	events := []game.Event{
+		{Type: game.EventNew, Player: player1},
+		{Type: game.EventJoin, Player: player1},
+		{Type: game.EventJoin, Player: player2},
+		{Type: game.EventJoin, Player: player3},
+		{Type: game.EventJoin, Player: player4},
+
+		// Player 4 gives up their seat before the game starts.
+		{Type: game.EventLeave, Player: player4},
+		{Type: game.EventStart, Player: player1},
+	}
+
+	// Player 3 is knocked out first, then player 2 loses to player 1.
+	for i := 0; i < rules.MaxOuts(); i++ {
+		events = append(events, game.Event{Type: game.EventLiar, Winner: player1, Loser: player3})
+	}
+	for i := 0; i < rules.MaxOuts(); i++ {
+		events = append(events, game.Event{Type: game.EventExact, Winner: player1, Loser: player2})
+	}
+	events = append(events, game.Event{Type: game.EventReconcile, Player: player1})
+
+	standings := tournament.Standings(events, rules)
+
+	exp := []common.Address{player1, player2, player3}
+	if len(standings) != len(exp) {
+		t.Fatalf("expecting %d players placed; got %d", len(exp), len(standings))
+	}
+
+	for i := range exp {
+		if standings[i] != exp[i] {
+			t.Fatalf("expecting place %d to be %s; got %s", i+1, exp[i], standings[i])
+		}
+	}
+}
@@ -56,7 +56,12 @@ type Test struct {
 // NewTest creates a test database inside a Docker container. It creates the
 // required table structure but the database is otherwise empty. It returns
 // the database to use as well as a function to call at the end of the test.
+// The test is skipped in short mode since the database isn't started.
 func NewTest(t *testing.T, c *docker.Container, testName string) *Test {
+	if testing.Short() {
+		t.Skip("skipping database test in short mode")
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
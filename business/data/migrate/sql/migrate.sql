@@ -59,3 +59,153 @@ CREATE TABLE game_balances
     PRIMARY KEY (game_id, round, player),
     FOREIGN KEY (game_id) REFERENCES games(game_id) ON DELETE CASCADE
 );
+
+-- Version: 1.02
+-- Description: Add provably fair seeds and commitments
+ALTER TABLE game_state
+    ADD COLUMN server_seed      VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN server_seed_hash VARCHAR NOT NULL DEFAULT '';
+
+ALTER TABLE game_cups
+    ADD COLUMN client_seed VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN commitment  VARCHAR NOT NULL DEFAULT '';
+
+-- Version: 1.03
+-- Description: Create the game event log
+CREATE TABLE game_events
+(
+    game_id      UUID      NOT NULL,
+    sequence     INT       NOT NULL,
+    type         VARCHAR   NOT NULL,
+    round        INT       NOT NULL,
+    player       VARCHAR   NOT NULL,
+    data         JSONB     NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (game_id, sequence),
+    FOREIGN KEY (game_id) REFERENCES games(game_id) ON DELETE CASCADE
+);
+
+-- Version: 1.04
+-- Description: Add the table settings to games
+ALTER TABLE games
+    ADD COLUMN ante_usd             DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN min_players          INT              NOT NULL DEFAULT 2,
+    ADD COLUMN max_players          INT              NOT NULL DEFAULT 5,
+    ADD COLUMN ruleset              VARCHAR          NOT NULL DEFAULT 'classic',
+    ADD COLUMN turn_timeout_seconds INT              NOT NULL DEFAULT 0;
+
+-- Version: 1.05
+-- Description: Add private tables
+ALTER TABLE games
+    ADD COLUMN private BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Version: 1.06
+-- Description: Create the tournament tables
+CREATE TABLE tournaments
+(
+    tournament_id UUID             NOT NULL,
+    creator       VARCHAR          NOT NULL,
+    buy_in_usd    DOUBLE PRECISION NOT NULL,
+    table_size    INT              NOT NULL,
+    prize_split   INT[]            NOT NULL,
+    status        VARCHAR          NOT NULL,
+    stage         INT              NOT NULL,
+    paid          INT              NOT NULL,
+    places        VARCHAR[]        NOT NULL,
+    date_created  TIMESTAMP        NOT NULL,
+
+    PRIMARY KEY (tournament_id)
+);
+
+CREATE TABLE tournament_players
+(
+    tournament_id UUID    NOT NULL,
+    player        VARCHAR NOT NULL,
+    seat          INT     NOT NULL,
+
+    PRIMARY KEY (tournament_id, player),
+    FOREIGN KEY (tournament_id) REFERENCES tournaments(tournament_id) ON DELETE CASCADE
+);
+
+CREATE TABLE tournament_tables
+(
+    tournament_id UUID      NOT NULL,
+    stage         INT       NOT NULL,
+    number        INT       NOT NULL,
+    game_id       UUID      NOT NULL,
+    players       VARCHAR[] NOT NULL,
+    standings     VARCHAR[] NOT NULL,
+    finished      BOOLEAN   NOT NULL,
+
+    PRIMARY KEY (tournament_id, stage, number),
+    FOREIGN KEY (tournament_id) REFERENCES tournaments(tournament_id) ON DELETE CASCADE
+);
+
+-- Version: 1.07
+-- Description: Create the player rating tables
+CREATE TABLE ratings
+(
+    player       VARCHAR          NOT NULL,
+    rating       DOUBLE PRECISION NOT NULL,
+    games        INT              NOT NULL,
+    wins         INT              NOT NULL,
+    date_updated TIMESTAMP        NOT NULL,
+
+    PRIMARY KEY (player)
+);
+
+CREATE TABLE rating_changes
+(
+    game_id       UUID             NOT NULL,
+    player        VARCHAR          NOT NULL,
+    rating_before DOUBLE PRECISION NOT NULL,
+    rating_after  DOUBLE PRECISION NOT NULL,
+    date_created  TIMESTAMP        NOT NULL,
+
+    PRIMARY KEY (game_id, player),
+    FOREIGN KEY (game_id) REFERENCES games(game_id) ON DELETE CASCADE
+);
+
+-- Version: 1.08
+-- Description: Create the game chat table
+CREATE TABLE game_chat
+(
+    chat_id      UUID      NOT NULL,
+    game_id      UUID      NOT NULL,
+    player       VARCHAR   NOT NULL,
+    message      TEXT      NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (chat_id),
+    FOREIGN KEY (game_id) REFERENCES games(game_id) ON DELETE CASCADE
+);
+
+CREATE INDEX game_chat_game_id_idx ON game_chat (game_id, date_created);
+
+-- Version: 1.09
+-- Description: Move the secret server seeds out of the game event log
+CREATE TABLE game_server_seeds
+(
+    game_id          UUID    NOT NULL,
+    sequence         INT     NOT NULL,
+    server_seed      VARCHAR NOT NULL,
+    next_server_seed VARCHAR NOT NULL,
+
+    PRIMARY KEY (game_id, sequence),
+    FOREIGN KEY (game_id) REFERENCES games(game_id) ON DELETE CASCADE
+);
+
+INSERT INTO game_server_seeds (game_id, sequence, server_seed, next_server_seed)
+SELECT
+    game_id,
+    sequence,
+    CASE WHEN type = 'new' THEN COALESCE(data->>'serverSeed', '') ELSE '' END,
+    COALESCE(data->>'nextServerSeed', '')
+FROM
+    game_events
+WHERE
+    type IN ('new', 'nextround');
+
+UPDATE game_events SET data = data - 'serverSeed' - 'nextServerSeed' WHERE type = 'new';
+UPDATE game_events SET data = data - 'nextServerSeed' WHERE type = 'nextround';
@@ -108,6 +108,28 @@ func StatusCheck(ctx context.Context, db *sqlx.DB) error {
 	return db.QueryRowContext(ctx, q).Scan(&tmp)
 }
 
+// WithinTran runs the function inside a transaction. The transaction is
+// committed when the function succeeds and rolled back otherwise.
+func WithinTran(ctx context.Context, log *logger.Logger, db *sqlx.DB, fn func(tx sqlx.ExtContext) error) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tran: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error(ctx, "database.WithinTran", "status", "rollback failed", "ERROR", rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tran: %w", err)
+	}
+
+	return nil
+}
+
 // ExecContext is a helper function to execute a CUD operation with
 // logging and tracing.
 func ExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string) error {
@@ -0,0 +1,88 @@
+// Package event provides the protocol for the events the engine sends to the
+// players and spectators of a game over the events web socket.
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// Version represents the version of the event protocol. It changes when the
+// envelope or a payload changes in a way that breaks existing clients.
+const Version = 1
+
+// Represents the types of events sent for a game.
+const (
+	TypeJoin      = "join"
+	TypeLeave     = "leave"
+	TypeStart     = "start"
+	TypeRollDice  = "rolldice"
+	TypeBet       = "bet"
+	TypeCallLiar  = "callliar"
+	TypeCallExact = "callexact"
+	TypeReconcile = "reconcile"
+	TypeRematch   = "rematch"
+	TypeTimeout   = "timeout"
+	TypeChat      = "chat"
+	TypeChatError = "chaterror"
+)
+
+// Envelope represents an event sent for a game. The payload depends on the
+// type of the event. Events that change the game carry a StatePayload, chat
+// events carry the message and chat errors carry the problem with the
+// message that was sent.
+type Envelope struct {
+	Version   int             `json:"version"`
+	Type      string          `json:"type"`
+	GameID    uuid.UUID       `json:"gameID"`
+	Sequence  int             `json:"sequence"` // The sequence of the last game event the payload reflects.
+	Timestamp time.Time       `json:"timestamp"`
+	Actor     common.Address  `json:"actor"` // The account that caused the event.
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// New constructs an envelope for the specified event and payload.
+func New(typ string, gameID uuid.UUID, sequence int, actor common.Address, payload any) (Envelope, error) {
+	env := Envelope{
+		Version:   Version,
+		Type:      typ,
+		GameID:    gameID,
+		Sequence:  sequence,
+		Timestamp: time.Now().UTC(),
+		Actor:     actor,
+	}
+
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return Envelope{}, fmt.Errorf("marshal payload: %w", err)
+		}
+		env.Payload = data
+	}
+
+	return env, nil
+}
+
+// Decode unmarshals the payload of the envelope into the specified value.
+func (env Envelope) Decode(v any) error {
+	if len(env.Payload) == 0 {
+		return fmt.Errorf("event %q has no payload", env.Type)
+	}
+
+	if err := json.Unmarshal(env.Payload, v); err != nil {
+		return fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	return nil
+}
+
+// StatePayload represents the state of the game after the event, redacted
+// for the account receiving it. Only that account's dice are provided.
+type StatePayload struct {
+	State  State  `json:"state"`
+	Action string `json:"action,omitempty"` // The action taken for an expired turn.
+}
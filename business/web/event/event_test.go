@@ -0,0 +1,72 @@
+package event_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ardanlabs/liarsdice/business/web/event"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+func Test_Envelope(t *testing.T) {
+	gameID := uuid.New()
+	actor := common.HexToAddress("0x8E113078ADF6888B7ba84967F299F29AeCe24c55")
+
+	payload := event.StatePayload{
+		State: event.State{
+			GameID: gameID,
+			Status: "playing",
+			Cups:   []event.Cup{{Player: actor, Dice: []int{1, 6, 6}}},
+		},
+		Action: "forfeit",
+	}
+
+	env, err := event.New(event.TypeTimeout, gameID, 7, actor, payload)
+	if err != nil {
+		t.Fatalf("unexpected error constructing the envelope: %s", err)
+	}
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling the envelope: %s", err)
+	}
+
+	var got event.Envelope
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unexpected error unmarshaling the envelope: %s", err)
+	}
+
+	if got.Version != event.Version || got.Type != event.TypeTimeout || got.Sequence != 7 {
+		t.Fatalf("expecting version %d type %s sequence 7; got version %d type %s sequence %d", event.Version, event.TypeTimeout, got.Version, got.Type, got.Sequence)
+	}
+
+	if got.GameID != gameID || got.Actor != actor {
+		t.Fatalf("expecting game %s actor %s; got game %s actor %s", gameID, actor, got.GameID, got.Actor)
+	}
+
+	if got.Timestamp.IsZero() {
+		t.Fatal("expecting the envelope to have a timestamp")
+	}
+
+	var gotPayload event.StatePayload
+	if err := got.Decode(&gotPayload); err != nil {
+		t.Fatalf("unexpected error decoding the payload: %s", err)
+	}
+
+	if gotPayload.Action != "forfeit" || gotPayload.State.Status != "playing" || len(gotPayload.State.Cups) != 1 || len(gotPayload.State.Cups[0].Dice) != 3 {
+		t.Fatalf("expecting payload %+v; got %+v", payload, gotPayload)
+	}
+}
+
+func Test_EnvelopeNoPayload(t *testing.T) {
+	env, err := event.New(event.TypeRematch, uuid.New(), 1, common.Address{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error constructing the envelope: %s", err)
+	}
+
+	var payload event.StatePayload
+	if err := env.Decode(&payload); err == nil {
+		t.Fatal("expecting an error decoding an envelope without a payload")
+	}
+}
@@ -0,0 +1,74 @@
+package event
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// State represents the state of a game as it's sent to a player or spectator.
+// Only the dice of the account receiving the state are provided.
+type State struct {
+	GameID             uuid.UUID        `json:"gameID"`
+	GameName           string           `json:"gameName"`
+	DateCreated        string           `json:"dateCreated"`
+	AnteUSD            float64          `json:"anteUSD"`
+	Status             string           `json:"status"`
+	Rules              Rules            `json:"rules"`
+	Palifico           bool             `json:"palifico"`
+	Reveal             *Reveal          `json:"reveal,omitempty"`
+	PlayerLastOut      common.Address   `json:"lastOut"`
+	PlayerLastWin      common.Address   `json:"lastWin"`
+	PlayerTurn         common.Address   `json:"currentID"`
+	Round              int              `json:"round"`
+	TurnDeadline       string           `json:"turnDeadline"`
+	Cups               []Cup            `json:"cups"`
+	ExistingPlayers    []common.Address `json:"playerOrder"`
+	Bets               []Bet            `json:"bets"`
+	Balances           []string         `json:"balances"`
+	ServerSeedHash     string           `json:"serverSeedHash"`
+	NextServerSeedHash string           `json:"nextServerSeedHash"`
+	InviteCode         string           `json:"inviteCode,omitempty"`
+}
+
+// Rules represents the rule variants the game is played with.
+type Rules struct {
+	Ruleset      string `json:"ruleset"`
+	MinPlayers   int    `json:"minPlayers"`
+	MaxPlayers   int    `json:"maxPlayers"`
+	TurnTimeout  string `json:"turnTimeout"`
+	Private      bool   `json:"private"`
+	Elimination  string `json:"elimination"`
+	MaxOuts      int    `json:"maxOuts"`
+	WildOnes     bool   `json:"wildOnes"`
+	SpotOn       bool   `json:"spotOn"`
+	Palifico     bool   `json:"palifico"`
+	TournamentID string `json:"tournamentID,omitempty"`
+}
+
+// Reveal represents the cups and outcome of the last round that ended.
+type Reveal struct {
+	Round  int            `json:"round"`
+	Cups   []Cup          `json:"cups"`
+	Bet    Bet            `json:"bet"`
+	Total  int            `json:"total"`
+	Exact  bool           `json:"exact"`
+	Winner common.Address `json:"winner"`
+	Loser  common.Address `json:"loser"`
+}
+
+// Bet represents a bet made by a player.
+type Bet struct {
+	Player common.Address `json:"account"`
+	Number int            `json:"number"`
+	Suit   int            `json:"suit"`
+}
+
+// Cup represents the cup of a player.
+type Cup struct {
+	Player     common.Address `json:"account"`
+	Dice       []int          `json:"dice"`
+	LastBet    Bet            `json:"lastBet"`
+	Outs       int            `json:"outs"`
+	ClientSeed string         `json:"clientSeed"`
+	Commitment string         `json:"commitment"`
+}
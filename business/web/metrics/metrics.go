@@ -20,6 +20,8 @@ type metrics struct {
 	requests   *expvar.Int
 	errors     *expvar.Int
 	panics     *expvar.Int
+	reconciled *expvar.Int
+	abandoned  *expvar.Int
 }
 
 // init constructs the metrics value that will be used to capture metrics.
@@ -32,6 +34,8 @@ func init() {
 		requests:   expvar.NewInt("requests"),
 		errors:     expvar.NewInt("errors"),
 		panics:     expvar.NewInt("panics"),
+		reconciled: expvar.NewInt("evicted_reconciled"),
+		abandoned:  expvar.NewInt("evicted_abandoned"),
 	}
 }
 
@@ -85,3 +89,23 @@ func AddPanics(ctx context.Context) int64 {
 
 	return 0
 }
+
+// AddReconciledEvictions increments the reconciled games evicted metric by 1.
+func AddReconciledEvictions(ctx context.Context) int64 {
+	if v, ok := ctx.Value(key).(*metrics); ok {
+		v.reconciled.Add(1)
+		return v.reconciled.Value()
+	}
+
+	return 0
+}
+
+// AddAbandonedEvictions increments the abandoned games evicted metric by 1.
+func AddAbandonedEvictions(ctx context.Context) int64 {
+	if v, ok := ctx.Value(key).(*metrics); ok {
+		v.abandoned.Add(1)
+		return v.abandoned.Value()
+	}
+
+	return 0
+}
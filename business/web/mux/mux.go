@@ -9,6 +9,9 @@ import (
 
 	"github.com/ardanlabs/ethereum/currency"
 	"github.com/ardanlabs/liarsdice/business/core/bank"
+	"github.com/ardanlabs/liarsdice/business/core/bot"
+	"github.com/ardanlabs/liarsdice/business/core/rating"
+	"github.com/ardanlabs/liarsdice/business/core/tournament"
 	"github.com/ardanlabs/liarsdice/business/web/auth"
 	"github.com/ardanlabs/liarsdice/business/web/mid"
 	"github.com/ardanlabs/liarsdice/foundation/logger"
@@ -37,10 +40,16 @@ type Config struct {
 	Converter      *currency.Converter
 	Bank           *bank.Bank
 	DB             *sqlx.DB
+	Bots           *bot.Bots
+	Tournaments    *tournament.Manager
+	Ratings        *rating.Core
 	AnteUSD        float64
+	TurnTimeout    time.Duration
+	TimeoutAction  string
 	ActiveKID      string
 	BankTimeout    time.Duration
 	ConnectTimeout time.Duration
+	InviteKey      []byte
 }
 
 // RouteAdder defines behavior that sets the routes to bind for an instance